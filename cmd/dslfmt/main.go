@@ -0,0 +1,164 @@
+// Command dslfmt formats alert configuration files.
+//
+// Usage:
+//
+//	dslfmt [-w] [file ...]
+//
+// Without files it formats the standard input. Files with syntax errors
+// are left unchanged and their diagnostics printed to standard error, so
+// are files with comments formatting would drop, e.g. inside a condition.
+// Rules are not validated: unknown functions and columns are formatted
+// as written. Files rewritten with -w keep their permissions.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/scanner"
+
+	dslalert "test"
+)
+
+func main() {
+	isWrite := flag.Bool("w", false, "write the result to the file instead of standard output")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		source, errRead := io.ReadAll(os.Stdin)
+		if errRead != nil {
+			fmt.Fprintln(os.Stderr, errRead)
+			os.Exit(2)
+		}
+
+		formatted, isValid := format("<stdin>", source)
+		if !isValid {
+			os.Exit(1)
+		}
+
+		fmt.Print(formatted)
+
+		return
+	}
+
+	var exitCode int
+
+	for _, path := range flag.Args() {
+		if errFormat := formatFile(path, *isWrite); errFormat != nil {
+			fmt.Fprintln(os.Stderr, errFormat)
+
+			exitCode = 1
+		}
+	}
+
+	os.Exit(exitCode)
+}
+
+func formatFile(path string, isWrite bool) error {
+	source, errRead := os.ReadFile(path)
+	if errRead != nil {
+		return errRead
+	}
+
+	formatted, isValid := format(path, source)
+	if !isValid {
+		return fmt.Errorf("%s: not formatted", path)
+	}
+
+	if !isWrite {
+		fmt.Print(formatted)
+
+		return nil
+	}
+
+	if bytes.Equal(source, []byte(formatted)) {
+		return nil
+	}
+
+	info, errStat := os.Stat(path)
+	if errStat != nil {
+		return errStat
+	}
+
+	return os.WriteFile(path, []byte(formatted), info.Mode().Perm())
+}
+
+func format(name string, source []byte) (string, bool) {
+	ast, diagnostics := dslalert.ParseWithParams(
+		bytes.NewReader(source),
+		&dslalert.ParamsParse{
+			IsSyntaxOnly: true,
+		},
+	)
+	if diagnostics.HasErrors() {
+		fmt.Fprintf(os.Stderr, "%s:\n%s", name, diagnostics.Render(string(source)))
+
+		return "", false
+	}
+
+	formatted := dslalert.Format(ast)
+
+	if dropped := droppedComment(string(source), formatted); dropped != "" {
+		fmt.Fprintf(os.Stderr, "%s: formatting would drop the comment %q\n", name, dropped)
+
+		return "", false
+	}
+
+	return formatted, true
+}
+
+// droppedComment returns a comment line of the source missing from the
+// formatted text, compared without comment markers as Format rewrites them.
+func droppedComment(source, formatted string) string {
+	counts := make(map[string]int)
+
+	for _, line := range commentLines(formatted) {
+		counts[line]++
+	}
+
+	for _, line := range commentLines(source) {
+		if counts[line] == 0 {
+			return line
+		}
+
+		counts[line]--
+	}
+
+	return ""
+}
+
+// commentLines lists the non-blank lines of the comments of a text.
+func commentLines(text string) []string {
+	var s scanner.Scanner
+
+	s.Init(strings.NewReader(text))
+	s.Mode = scanner.GoTokens &^ scanner.SkipComments
+	s.Error = func(*scanner.Scanner, string) {}
+
+	var result []string
+
+	for current := s.Scan(); current != scanner.EOF; current = s.Scan() {
+		if current != scanner.Comment {
+			continue
+		}
+
+		raw := s.TokenText()
+
+		if content, isLine := strings.CutPrefix(raw, "//"); isLine {
+			raw = content
+		} else {
+			raw = strings.TrimSuffix(strings.TrimPrefix(raw, "/*"), "*/")
+		}
+
+		for line := range strings.SplitSeq(raw, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				result = append(result, line)
+			}
+		}
+	}
+
+	return result
+}
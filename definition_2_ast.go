@@ -1,29 +1,100 @@
 package dslalert
 
-type expression interface {
-	interfaceMarker() // added as other types could implement stringers.
-	string() string
+import "text/scanner"
+
+// Span locates a node in the source, from its first token
+// up to right after its last one.
+type Span struct {
+	Start scanner.Position
+	End   scanner.Position
+}
+
+// Location returns the span itself, so nodes embedding a Span are Nodes.
+func (s Span) Location() Span {
+	return s
+}
+
+// Node is any node of the alert configuration tree.
+type Node interface {
+	Location() Span
 }
 
-var _ expression = &expressionBinary{}
-var _ expression = &expressionLiteral{}
-var _ expression = &expressionVariable{}
+// Expression is a node of a rule condition.
+// The set of expressions is closed, it is the Expression... types below.
+type Expression interface {
+	Node
+
+	expressionNode() // keeps the set closed, as other types could implement stringers.
+	String() string
+}
+
+var _ Expression = &ExpressionBinary{}
+var _ Expression = &ExpressionLiteral{}
+var _ Expression = &ExpressionVariable{}
+var _ Expression = &ExpressionUnary{}
+var _ Expression = &ExpressionCall{}
+var _ Expression = &ExpressionList{}
+var _ Expression = &ExpressionBetween{}
+var _ Expression = &ExpressionConditional{}
+
+var _ Node = &Rule{}
+var _ Node = &Monitor{}
+var _ Node = &Criteria{}
+var _ Node = &AlertConfiguration{}
+
+type Rule struct {
+	Span
 
-type rule struct {
 	Level     int
-	Condition expression // the 'when' condition expression
+	Condition Expression // the 'when' condition expression
+
+	Detached []string // comment groups above Doc, separated by blank lines
+	Doc      string   // comment lines right above the rule
+	Comment  string   // comment following the rule on its line
+
+	// IsError marks a rule the parser could not read completely, kept only
+	// by tolerant parsing. It is not evaluated.
+	IsError bool
 }
 
-type monitor struct {
+type Monitor struct {
+	Span
+
 	ColumnName string
-	Rules      []*rule
+	Rules      []*Rule
+
+	Detached       []string // comment groups above Doc, separated by blank lines
+	Doc            string   // comment lines right above the monitor
+	CommentOpening string   // comment following its opening brace
+	Dangling       []string // comment groups after the last rule
+	Comment        string   // comment following its closing brace
+
+	// IsError marks a monitor the parser could not read completely, kept only
+	// by tolerant parsing. Its well-formed rules are kept, it is not evaluated.
+	IsError bool
 }
 
-type criteria struct {
+type Criteria struct {
+	Span
+
 	Name     string
-	Monitors []*monitor
+	Monitors []*Monitor
+
+	Detached       []string // comment groups above Doc, separated by blank lines
+	Doc            string   // comment lines right above the criteria
+	CommentOpening string   // comment following its opening brace
+	Dangling       []string // comment groups after the last monitor
+	Comment        string   // comment following its closing brace
+
+	// IsError marks a criteria the parser could not read completely, kept only
+	// by tolerant parsing. Its well-formed monitors are kept, it is not evaluated.
+	IsError bool
 }
 
 type AlertConfiguration struct {
-	Criterias []*criteria
+	Span
+
+	Criterias []*Criteria
+
+	Dangling []string // comment groups after the last criteria
 }
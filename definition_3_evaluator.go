@@ -1,6 +1,9 @@
 package dslalert
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type EvaluationResult struct {
 	ValueCurrent any
@@ -9,18 +12,34 @@ type EvaluationResult struct {
 	MonitorName  string
 	Row          string
 
+	// doc comments of the matching nodes, for reports.
+	CriteriaDoc string
+	MonitorDoc  string
+	RuleDoc     string
+
 	RowIndex  int
 	RuleLevel int
 }
 
 func (e EvaluationResult) String() string {
-	return fmt.Sprintf(
+	result := fmt.Sprintf(
 		"Row %d: %s --> Alert triggered! Level=%d (Value=%v)",
 		e.RowIndex,
 		e.Row,
 		e.RuleLevel,
 		e.ValueCurrent,
 	)
+
+	if e.RuleDoc == "" {
+		return result
+	}
+
+	return result + " - " + docInline(e.RuleDoc)
+}
+
+// docInline folds a multi-line doc comment into one line.
+func docInline(doc string) string {
+	return strings.ReplaceAll(doc, "\n", " ")
 }
 
 type EvaluationResults []EvaluationResult
@@ -52,13 +71,19 @@ func (results EvaluationResults) Message() string {
 		return ""
 	}
 
-	return fmt.Sprintf(
+	result := fmt.Sprintf(
 		"Level maximum for criteria %s (monitor %s): %d",
 
 		results[0].CriteriaName,
 		results[0].MonitorName,
 		results.LevelMaximum(),
 	)
+
+	if results[0].CriteriaDoc == "" {
+		return result
+	}
+
+	return result + " - " + docInline(results[0].CriteriaDoc)
 }
 
 func (results EvaluationResults) String() string {
@@ -70,3 +95,108 @@ func (results EvaluationResults) String() string {
 
 	return result
 }
+
+// EvaluationError is a row a criteria could not evaluate fully: a line not
+// split in as many fields as the header, skipped, or a rule whose condition
+// failed, the rules of lower level being tried next.
+type EvaluationError struct {
+	CriteriaName string
+	MonitorName  string // empty for a line skipped
+
+	RowIndex  int
+	RuleLevel int // zero for a line skipped
+
+	Err error
+}
+
+func (e EvaluationError) Error() string {
+	if e.MonitorName == "" {
+		return fmt.Sprintf(
+			"row %d, criteria '%s': %v",
+			e.RowIndex,
+			e.CriteriaName,
+			e.Err,
+		)
+	}
+
+	return fmt.Sprintf(
+		"row %d, criteria '%s', monitor '%s', level %d: %v",
+		e.RowIndex,
+		e.CriteriaName,
+		e.MonitorName,
+		e.RuleLevel,
+		e.Err,
+	)
+}
+
+func (e EvaluationError) Unwrap() error {
+	return e.Err
+}
+
+// EvaluationErrors are the errors of an evaluation, in row order.
+type EvaluationErrors []EvaluationError
+
+func (errs EvaluationErrors) Error() string {
+	messages := make([]string, len(errs))
+
+	for ix, err := range errs {
+		messages[ix] = err.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (errs EvaluationErrors) Unwrap() []error {
+	result := make([]error, len(errs))
+
+	for ix, err := range errs {
+		result[ix] = err
+	}
+
+	return result
+}
+
+// Err returns the list as an error when it holds any, nil otherwise.
+func (errs EvaluationErrors) Err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// CriteriaResults are the results of one criteria of a configuration,
+// with the rows it could not evaluate fully.
+type CriteriaResults struct {
+	CriteriaName string
+	Results      EvaluationResults
+	Errors       EvaluationErrors
+}
+
+// ConfigurationResults holds the results of every criteria
+// of a configuration, in configuration order.
+type ConfigurationResults []CriteriaResults
+
+// Criteria returns the results of the criteria with the given name,
+// nil when there is no such criteria or it matched no row.
+func (results ConfigurationResults) Criteria(name string) EvaluationResults {
+	for _, r := range results {
+		if r.CriteriaName == name {
+			return r.Results
+		}
+	}
+
+	return nil
+}
+
+// Err returns the errors of all the criteria as one error,
+// nil when every row evaluated.
+func (results ConfigurationResults) Err() error {
+	var result EvaluationErrors
+
+	for _, r := range results {
+		result = append(result, r.Errors...)
+	}
+
+	return result.Err()
+}
@@ -1,6 +1,10 @@
 package dslalert
 
-import "text/scanner"
+import (
+	"fmt"
+	"slices"
+	"text/scanner"
+)
 
 type tokenKind int
 
@@ -18,7 +22,20 @@ const (
 	tokenRightBrace    // }
 	tokenAssign        // =
 	tokenSemicolon     // ;
-	tokenOperator      // >, >=, <, <=, ==, !=, +, -, *, /
+	tokenOperator      // >, >=, <, <=, ==, !=, +, -, *, /, !, and, or, not, contains, startswith, endswith, matches, in, between
+	tokenLeftParen     // (
+	tokenRightParen    // )
+	tokenWithin        // within, tolerance of == and !=
+	tokenComma         // ,
+	tokenBoolean       // true, false
+	tokenNull          // null
+	tokenLeftBracket   // [
+	tokenRightBracket  // ]
+	tokenIf            // if
+	tokenThen          // then
+	tokenElse          // else
+	tokenCase          // case
+	tokenEnd           // end
 )
 
 const (
@@ -26,11 +43,113 @@ const (
 	_dslMonitor  = "monitor"
 	_dslLevel    = "level"
 	_dslWhen     = "when"
+	_dslWithin   = "within"
+	_dslValue    = "value" // variable bound to the monitored column
+	_dslTrue     = "true"
+	_dslFalse    = "false"
+	_dslNull     = "null"
+
+	_dslIf   = "if"
+	_dslThen = "then"
+	_dslElse = "else"
+	_dslCase = "case"
+	_dslEnd  = "end"
+
+	_dslAnd = "and"
+	_dslOr  = "or"
+	_dslNot = "not"
+
+	_dslContains   = "contains"
+	_dslStartsWith = "startswith"
+	_dslEndsWith   = "endswith"
+	_dslMatches    = "matches"
+
+	_dslIn      = "in"
+	_dslNotIn   = "not in"
+	_dslBetween = "between"
 )
 
+// _keywords is the reserved word set, also the candidates of "did you mean".
+var _keywords = []string{
+	_dslCriteria, _dslMonitor, _dslLevel, _dslWhen, _dslWithin,
+	_dslAnd, _dslOr, _dslNot,
+	_dslContains, _dslStartsWith, _dslEndsWith, _dslMatches,
+	_dslIn, _dslBetween,
+	_dslTrue, _dslFalse, _dslNull,
+	_dslIf, _dslThen, _dslElse, _dslCase, _dslEnd,
+}
+
+// _tokenSpellings names token kinds the way authors write them.
+var _tokenSpellings = map[tokenKind]string{
+	tokenEOF:           "end of input",
+	tokenError:         "invalid token",
+	tokenIdentifier:    "identifier",
+	tokenCriteria:      _dslCriteria,
+	tokenMonitor:       _dslMonitor,
+	tokenLevel:         _dslLevel,
+	tokenWhen:          _dslWhen,
+	tokenStringLiteral: "string",
+	tokenNumber:        "number",
+	tokenLeftBrace:     "{",
+	tokenRightBrace:    "}",
+	tokenAssign:        "=",
+	tokenSemicolon:     ";",
+	tokenOperator:      "operator",
+	tokenLeftParen:     "(",
+	tokenRightParen:    ")",
+	tokenWithin:        _dslWithin,
+	tokenComma:         ",",
+	tokenBoolean:       "boolean",
+	tokenNull:          _dslNull,
+	tokenLeftBracket:   "[",
+	tokenRightBracket:  "]",
+	tokenIf:            _dslIf,
+	tokenThen:          _dslThen,
+	tokenElse:          _dslElse,
+	tokenCase:          _dslCase,
+	tokenEnd:           _dslEnd,
+}
+
+func (k tokenKind) String() string {
+	if spelling, exists := _tokenSpellings[k]; exists {
+		return spelling
+	}
+
+	return fmt.Sprintf("token(%d)", int(k))
+}
+
 // token represents a single token from the input.
 type token struct {
 	kind         tokenKind
 	valueLiteral string // literal value of the token (e.g., "my_col", ">=", "100")
 	pos          scanner.Position
+	end          scanner.Position // position right after the token
+
+	doc              string   // comment group on the lines right above the token
+	commentsDetached []string // comment groups above doc, separated by blank lines
+	commentTrailing  string   // comment following the previous token on its line
+}
+
+// commentGroups returns all the comment groups above the token, doc last.
+func (t token) commentGroups() []string {
+	if t.doc == "" {
+		return t.commentsDetached
+	}
+
+	return append(slices.Clip(t.commentsDetached), t.doc)
+}
+
+// describe names the token for messages, with its spelling when the kind
+// alone does not tell it, e.g. identifier 'monitr'.
+func (t token) describe() string {
+	switch t.kind {
+	case tokenIdentifier, tokenNumber, tokenOperator, tokenBoolean:
+		return fmt.Sprintf("%s '%s'", t.kind, t.valueLiteral)
+
+	case tokenStringLiteral:
+		return fmt.Sprintf("%s %q", t.kind, t.valueLiteral)
+
+	default:
+		return t.kind.String()
+	}
 }
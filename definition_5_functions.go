@@ -0,0 +1,253 @@
+package dslalert
+
+import (
+	"errors"
+	"math"
+	"slices"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// arityVariadic marks a function accepting any number of arguments
+// above its minimum.
+const arityVariadic = -1
+
+// function is a function callable from conditions, e.g. abs(value - 100).
+// Built-ins provide implementationNumeric, host functions registered
+// through a FunctionRegistry provide implementation.
+type function struct {
+	arityMinimum int
+	arityMaximum int // arityVariadic for no upper bound
+
+	parameters []Kind // last kind repeats for variadic functions
+	result     Kind
+
+	implementationNumeric func(arguments []float64) (float64, error)
+	implementation        func(arguments []any) (any, error)
+}
+
+func (f *function) acceptsArity(arity int) bool {
+	if arity < f.arityMinimum {
+		return false
+	}
+
+	return f.arityMaximum == arityVariadic || arity <= f.arityMaximum
+}
+
+// parameterKind returns the kind expected for the argument at position ix.
+func (f *function) parameterKind(ix int) Kind {
+	if f.implementationNumeric != nil || len(f.parameters) == 0 {
+		return KindNumber
+	}
+
+	if ix >= len(f.parameters) {
+		return f.parameters[len(f.parameters)-1]
+	}
+
+	return f.parameters[ix]
+}
+
+// Function describes a host function exposed to rule authors,
+// e.g. business_days_since(value) or fx_convert(value, "EUR").
+//
+// Arguments reach Implementation as float64, string or bool according to
+// the declared kinds, and the returned value must match Result.
+type Function struct {
+	Parameters []Kind
+	IsVariadic bool // last parameter accepts any number of arguments
+
+	Result Kind
+
+	Implementation func(arguments []any) (any, error)
+}
+
+// FunctionRegistry holds the host functions available to one configuration.
+// Pass it to ParseWithParams so different tenants can expose different sets.
+// Built-in functions are always available and cannot be redefined.
+type FunctionRegistry struct {
+	functions map[string]*function
+}
+
+func NewFunctionRegistry() *FunctionRegistry {
+	return &FunctionRegistry{
+		functions: make(map[string]*function),
+	}
+}
+
+func (r *FunctionRegistry) Register(name string, definition Function) error {
+	if !isIdentifier(name) || isKeyword(name) {
+		return goerrors.ErrValidation{
+			Caller: "Register",
+			Issue: goerrors.ErrInvalidInput{
+				InputName:  "name",
+				InputValue: name,
+				Issue:      errors.New("function name must be a non-keyword identifier"),
+			},
+		}
+	}
+
+	if definition.Implementation == nil {
+		return goerrors.ErrValidation{
+			Caller: "Register",
+			Issue: goerrors.ErrNilInput{
+				InputName: "Implementation",
+			},
+		}
+	}
+
+	if definition.IsVariadic && len(definition.Parameters) == 0 {
+		return goerrors.ErrValidation{
+			Caller: "Register",
+			Issue: goerrors.ErrInvalidInput{
+				InputName:  "Parameters",
+				InputValue: definition.Parameters,
+				Issue:      errors.New("variadic function needs at least one parameter"),
+			},
+		}
+	}
+
+	if _, isBuiltin := functionsBuiltin[name]; isBuiltin {
+		return goerrors.ErrDatasetEntryAlreadyExists{
+			Caller: "Register",
+			Entry:  name,
+		}
+	}
+
+	if _, exists := r.functions[name]; exists {
+		return goerrors.ErrDatasetEntryAlreadyExists{
+			Caller: "Register",
+			Entry:  name,
+		}
+	}
+
+	registered := function{
+		arityMinimum: len(definition.Parameters),
+		arityMaximum: len(definition.Parameters),
+
+		parameters: slices.Clone(definition.Parameters),
+		result:     definition.Result,
+
+		implementation: definition.Implementation,
+	}
+
+	if definition.IsVariadic {
+		registered.arityMaximum = arityVariadic
+	}
+
+	r.functions[name] = &registered
+
+	return nil
+}
+
+// lookup resolves a function name against the registry, then the built-ins.
+// A nil registry only resolves built-ins.
+func (r *FunctionRegistry) lookup(name string) (*function, bool) {
+	if r != nil {
+		if registered, exists := r.functions[name]; exists {
+			return registered, true
+		}
+	}
+
+	builtin, exists := functionsBuiltin[name]
+
+	return builtin, exists
+}
+
+var functionsBuiltin = map[string]*function{
+	"abs": {
+		arityMinimum: 1,
+		arityMaximum: 1,
+		result:       KindNumber,
+		implementationNumeric: func(arguments []float64) (float64, error) {
+			return math.Abs(arguments[0]), nil
+		},
+	},
+
+	"min": {
+		arityMinimum: 2,
+		arityMaximum: arityVariadic,
+		result:       KindNumber,
+		implementationNumeric: func(arguments []float64) (float64, error) {
+			result := arguments[0]
+
+			for _, argument := range arguments[1:] {
+				result = math.Min(result, argument)
+			}
+
+			return result, nil
+		},
+	},
+
+	"max": {
+		arityMinimum: 2,
+		arityMaximum: arityVariadic,
+		result:       KindNumber,
+		implementationNumeric: func(arguments []float64) (float64, error) {
+			result := arguments[0]
+
+			for _, argument := range arguments[1:] {
+				result = math.Max(result, argument)
+			}
+
+			return result, nil
+		},
+	},
+
+	"round": {
+		arityMinimum: 1,
+		arityMaximum: 2, // optional number of decimals
+		result:       KindNumber,
+		implementationNumeric: func(arguments []float64) (float64, error) {
+			if len(arguments) == 1 {
+				return math.Round(arguments[0]), nil
+			}
+
+			scale := math.Pow(10, math.Trunc(arguments[1]))
+
+			return math.Round(arguments[0]*scale) / scale, nil
+		},
+	},
+
+	"log": {
+		arityMinimum: 1,
+		arityMaximum: 1,
+		result:       KindNumber,
+		implementationNumeric: func(arguments []float64) (float64, error) {
+			if arguments[0] <= 0 {
+				return 0,
+					errors.New("logarithm of a non-positive number")
+			}
+
+			return math.Log(arguments[0]), nil
+		},
+	},
+
+	"sqrt": {
+		arityMinimum: 1,
+		arityMaximum: 1,
+		result:       KindNumber,
+		implementationNumeric: func(arguments []float64) (float64, error) {
+			if arguments[0] < 0 {
+				return 0,
+					errors.New("square root of a negative number")
+			}
+
+			return math.Sqrt(arguments[0]), nil
+		},
+	},
+
+	"pow": {
+		arityMinimum: 2,
+		arityMaximum: 2,
+		result:       KindNumber,
+		implementationNumeric: func(arguments []float64) (float64, error) {
+			result := math.Pow(arguments[0], arguments[1])
+			if math.IsNaN(result) || math.IsInf(result, 0) {
+				return 0,
+					errors.New("result is not a finite number")
+			}
+
+			return result, nil
+		},
+	},
+}
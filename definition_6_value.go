@@ -0,0 +1,192 @@
+package dslalert
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Kind is the dynamic type of a value flowing through a condition.
+type Kind int
+
+const (
+	KindAny Kind = iota // no constraint, accepts every kind
+	KindNumber
+	KindString
+	KindBool
+	KindNull
+	KindList
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindAny:
+		return "any"
+	case KindNumber:
+		return "number"
+	case KindString:
+		return "string"
+	case KindBool:
+		return "bool"
+	case KindNull:
+		return "null"
+	case KindList:
+		return "list"
+
+	default:
+		return "unknown"
+	}
+}
+
+// kindAccepts reports whether a value of kind actual satisfies expected.
+func kindAccepts(expected, actual Kind) bool {
+	return expected == KindAny || actual == KindAny || expected == actual
+}
+
+// Value is a typed value produced while evaluating a condition.
+//
+// Coercion rules:
+//   - arithmetic needs numbers, '+' also concatenates two strings;
+//   - ordering (<, <=, >, >=) needs two numbers or two strings;
+//   - equality (==, !=) never fails: values of different kinds are not equal;
+//   - 'in' needs a list on its right side, 'between' orders like '<=';
+//   - 'and', 'or', 'not' and '!' need booleans.
+type Value struct {
+	kind Kind
+
+	number  float64
+	text    string
+	boolean bool
+	list    []Value
+}
+
+func numberValue(number float64) Value {
+	return Value{
+		kind:   KindNumber,
+		number: number,
+	}
+}
+
+func stringValue(text string) Value {
+	return Value{
+		kind: KindString,
+		text: text,
+	}
+}
+
+func boolValue(boolean bool) Value {
+	return Value{
+		kind:    KindBool,
+		boolean: boolean,
+	}
+}
+
+func listValue(list []Value) Value {
+	return Value{
+		kind: KindList,
+		list: list,
+	}
+}
+
+func nullValue() Value {
+	return Value{
+		kind: KindNull,
+	}
+}
+
+func (v Value) Kind() Kind {
+	return v.kind
+}
+
+func (v Value) String() string {
+	switch v.kind {
+	case KindNumber:
+		return strconv.FormatFloat(v.number, 'g', -1, 64)
+	case KindString:
+		return strconv.Quote(v.text)
+	case KindBool:
+		return strconv.FormatBool(v.boolean)
+	case KindList:
+		elements := make([]string, len(v.list))
+
+		for ix, element := range v.list {
+			elements[ix] = element.String()
+		}
+
+		return "[" + strings.Join(elements, ", ") + "]"
+
+	default:
+		return _dslNull
+	}
+}
+
+// any returns the Go representation: float64, string, bool, []any or nil.
+func (v Value) any() any {
+	switch v.kind {
+	case KindNumber:
+		return v.number
+	case KindString:
+		return v.text
+	case KindBool:
+		return v.boolean
+	case KindList:
+		result := make([]any, len(v.list))
+
+		for ix, element := range v.list {
+			result[ix] = element.any()
+		}
+
+		return result
+
+	default:
+		return nil
+	}
+}
+
+// valueFromAny converts float64, string, bool or nil (as returned by host
+// functions) into a Value. Other numeric Go types are widened to float64.
+func valueFromAny(raw any) (Value, bool) {
+	switch typed := raw.(type) {
+	case nil:
+		return nullValue(), true
+	case string:
+		return stringValue(typed), true
+	case bool:
+		return boolValue(typed), true
+	}
+
+	if number, isNumeric := toFloat64(raw); isNumeric {
+		return numberValue(number), true
+	}
+
+	return Value{}, false
+}
+
+// parseCell types a raw CSV cell:
+// empty or null is null, a quoted cell is a string, then number, then
+// true / false, anything else is a string.
+func parseCell(raw string) Value {
+	cell := strings.TrimSpace(raw)
+
+	if cell == "" || strings.EqualFold(cell, _dslNull) {
+		return nullValue()
+	}
+
+	if len(cell) >= 2 && cell[0] == '"' && cell[len(cell)-1] == '"' {
+		return stringValue(cell[1 : len(cell)-1])
+	}
+
+	if number, err := strconv.ParseFloat(cell, 64); err == nil {
+		return numberValue(number)
+	}
+
+	if boolean, err := strconv.ParseBool(cell); err == nil && isBooleanWord(cell) {
+		return boolValue(boolean)
+	}
+
+	return stringValue(cell)
+}
+
+// isBooleanWord restricts strconv.ParseBool to the words, not 1 / 0 / t / f.
+func isBooleanWord(cell string) bool {
+	return strings.EqualFold(cell, _dslTrue) || strings.EqualFold(cell, _dslFalse)
+}
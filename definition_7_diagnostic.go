@@ -0,0 +1,146 @@
+package dslalert
+
+import (
+	"fmt"
+	"strings"
+	"text/scanner"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota + 1
+	SeverityWarning
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+
+	default:
+		return "unknown"
+	}
+}
+
+// Code is the stable identifier of a diagnostic.
+// Codes are errors so errors.Is(err, CodeUnexpectedToken) matches any
+// diagnostic carrying that code.
+type Code string
+
+func (c Code) Error() string {
+	return string(c)
+}
+
+const (
+	CodeEmptyInput       Code = "DSL001"
+	CodeInvalidCharacter Code = "DSL002"
+	CodeInvalidString    Code = "DSL003"
+	CodeUnexpectedToken  Code = "DSL004"
+	CodeInvalidNumber    Code = "DSL005"
+	CodeEmptyBlock       Code = "DSL006"
+	CodeUnknownFunction  Code = "DSL007"
+	CodeArity            Code = "DSL008"
+	CodeArgumentKind     Code = "DSL009"
+	CodeInvalidPattern   Code = "DSL010"
+	CodeInvalidCondition Code = "DSL011"
+
+	// semantic checks of Validate
+	CodeDuplicateCriteria   Code = "DSL012"
+	CodeDuplicateMonitor    Code = "DSL013"
+	CodeDuplicateLevel      Code = "DSL014"
+	CodeInvalidLevel        Code = "DSL015"
+	CodeUndefinedVariable   Code = "DSL016"
+	CodeConditionNotBoolean Code = "DSL017"
+	CodeTypeMismatch        Code = "DSL018"
+
+	// findings of the linter, see LintChecks
+	CodeUnreachableLevel Code = "DSL019"
+	CodeLevelGap         Code = "DSL020"
+	CodeNonMonotonic     Code = "DSL021"
+	CodeRepeatedNumber   Code = "DSL022"
+	CodeMissingDoc       Code = "DSL023"
+	CodeLevelStart       Code = "DSL024"
+	CodeTooManyMonitors  Code = "DSL025"
+	CodeFloatEquality    Code = "DSL026"
+)
+
+// Diagnostic is a problem found in an alert configuration, located by
+// the span Start..End so editors can underline it.
+type Diagnostic struct {
+	Severity Severity
+	Code     Code
+
+	Start scanner.Position
+	End   scanner.Position
+
+	Message  string
+	Expected []string // tokens that would have been accepted, if any
+	Hint     string   // optional suggestion to fix the problem
+}
+
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf(
+		"%s: %s %s: %s",
+
+		d.Start,
+		d.Severity,
+		d.Code,
+		d.Message,
+	)
+}
+
+func (d Diagnostic) Is(target error) bool {
+	code, isCode := target.(Code)
+
+	return isCode && code == d.Code
+}
+
+// Diagnostics is the list of problems reported for one input.
+// It works with errors.Is and errors.As through Unwrap.
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	messages := make([]string, len(d))
+
+	for ix, diagnostic := range d {
+		messages[ix] = diagnostic.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (d Diagnostics) Unwrap() []error {
+	result := make([]error, len(d))
+
+	for ix, diagnostic := range d {
+		result[ix] = diagnostic
+	}
+
+	return result
+}
+
+func (d Diagnostics) HasErrors() bool {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Err returns the list as an error when it holds at least one error,
+// nil otherwise, so warnings alone do not fail a caller.
+func (d Diagnostics) Err() error {
+	if !d.HasErrors() {
+		return nil
+	}
+
+	return d
+}
@@ -0,0 +1,190 @@
+package dslalert
+
+import "regexp"
+
+// opcode is an instruction of the bytecode VM. Operands follow the opcode
+// in the code as big-endian uint16, see _opcodeOperands.
+type opcode byte
+
+const (
+	opConstant opcode = iota + 1 // push constants[operand]
+	opValue                      // push the monitored value
+	opColumn                     // push the column names[operand] of the row
+
+	opNot    // unary not / !
+	opNegate // unary -
+	opPlus   // unary +
+
+	opAdd
+	opSubtract
+	opMultiply
+	opDivide
+
+	opGreater
+	opGreaterEqual
+	opLess
+	opLessEqual
+	opEqual
+	opNotEqual
+	opEqualWithin    // pops the tolerance above both operands
+	opNotEqualWithin // pops the tolerance above both operands
+
+	opIn
+	opNotIn
+	opBetween    // pops upper, lower, operand
+	opNotBetween // pops upper, lower, operand
+
+	opContains
+	opStartsWith
+	opEndsWith
+	opMatches // operand: patterns[operand]
+
+	opList // operand: number of elements
+	opCall // operands: functions[operand], number of arguments
+
+	opJump             // operand: target
+	opJumpIfFalseOrPop // 'and': left side decides when false
+	opJumpIfTrueOrPop  // 'or': left side decides when true
+	opLogicalRight     // operand: names[operand], the operator whose right side must be bool
+	opJumpIfNotThen    // 'if': pops the condition, jumps to the alternative when false
+	opReturn           // the top of the stack is the condition result
+)
+
+// _opcodeOperands is the number of uint16 operands of each opcode.
+var _opcodeOperands = [opReturn + 1]int{
+	opConstant:         1,
+	opColumn:           1,
+	opMatches:          1,
+	opList:             1,
+	opCall:             2,
+	opJump:             1,
+	opJumpIfFalseOrPop: 1,
+	opJumpIfTrueOrPop:  1,
+	opLogicalRight:     1,
+	opJumpIfNotThen:    1,
+}
+
+var _opcodeNames = [opReturn + 1]string{
+	opConstant: "CONSTANT",
+	opValue:    "VALUE",
+	opColumn:   "COLUMN",
+
+	opNot:    "NOT",
+	opNegate: "NEGATE",
+	opPlus:   "PLUS",
+
+	opAdd:      "ADD",
+	opSubtract: "SUBTRACT",
+	opMultiply: "MULTIPLY",
+	opDivide:   "DIVIDE",
+
+	opGreater:        "GREATER",
+	opGreaterEqual:   "GREATER_EQUAL",
+	opLess:           "LESS",
+	opLessEqual:      "LESS_EQUAL",
+	opEqual:          "EQUAL",
+	opNotEqual:       "NOT_EQUAL",
+	opEqualWithin:    "EQUAL_WITHIN",
+	opNotEqualWithin: "NOT_EQUAL_WITHIN",
+
+	opIn:         "IN",
+	opNotIn:      "NOT_IN",
+	opBetween:    "BETWEEN",
+	opNotBetween: "NOT_BETWEEN",
+
+	opContains:   "CONTAINS",
+	opStartsWith: "STARTS_WITH",
+	opEndsWith:   "ENDS_WITH",
+	opMatches:    "MATCHES",
+
+	opList: "LIST",
+	opCall: "CALL",
+
+	opJump:             "JUMP",
+	opJumpIfFalseOrPop: "JUMP_IF_FALSE_OR_POP",
+	opJumpIfTrueOrPop:  "JUMP_IF_TRUE_OR_POP",
+	opLogicalRight:     "LOGICAL_RIGHT",
+	opJumpIfNotThen:    "JUMP_IF_NOT_THEN",
+	opReturn:           "RETURN",
+}
+
+func (op opcode) String() string {
+	if int(op) < len(_opcodeNames) && _opcodeNames[op] != "" {
+		return _opcodeNames[op]
+	}
+
+	return "UNKNOWN"
+}
+
+// _opcodeOperators maps the opcodes applying a DSL operator to it,
+// for the shared evaluation helpers and their messages.
+var _opcodeOperators = [opReturn + 1]string{
+	opNot:    _dslNot,
+	opNegate: "-",
+	opPlus:   "+",
+
+	opAdd:      "+",
+	opSubtract: "-",
+	opMultiply: "*",
+	opDivide:   "/",
+
+	opGreater:        ">",
+	opGreaterEqual:   ">=",
+	opLess:           "<",
+	opLessEqual:      "<=",
+	opEqual:          "==",
+	opNotEqual:       "!=",
+	opEqualWithin:    "==",
+	opNotEqualWithin: "!=",
+
+	opIn:    _dslIn,
+	opNotIn: _dslNotIn,
+
+	opContains:   _dslContains,
+	opStartsWith: _dslStartsWith,
+	opEndsWith:   _dslEndsWith,
+	opMatches:    _dslMatches,
+}
+
+// Bytecode is the compiled form of the rules of a configuration: one code
+// section holding every condition, the pools the instructions refer to,
+// and the layout of criteria, monitors and rules needed to report results.
+// It is immutable once built and can be saved with MarshalBinary.
+type Bytecode struct {
+	code []byte
+
+	constants []Value
+	names     []string // columns and operators
+	patterns  []*regexp.Regexp
+	functions []bytecodeFunction
+
+	criterias []bytecodeCriteria
+
+	stackMaximum int // deepest stack any condition needs
+}
+
+type bytecodeFunction struct {
+	name     string
+	function *function
+}
+
+type bytecodeCriteria struct {
+	name string
+	doc  string
+
+	monitors []bytecodeMonitor
+}
+
+type bytecodeMonitor struct {
+	columnName string
+	doc        string
+
+	rules []bytecodeRule // by level descending
+}
+
+type bytecodeRule struct {
+	level int
+	doc   string
+
+	entry int // offset of the condition in the code
+}
@@ -0,0 +1,86 @@
+package dslalert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rowValue builds a row context holding only the monitored value.
+func rowValue(value float64) *rowContext {
+	return &rowContext{
+		value: numberValue(value),
+	}
+}
+
+func TestEvaluateLogical(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		value     float64
+		wantMatch bool
+	}{
+		{"range inside", "value > 5 and value < 50", 10, true},
+		{"range below", "value > 5 and value < 50", 3, false},
+		{"range above", "value > 5 and value < 50", 60, false},
+		{"or either side", "value < 5 or value > 50", 60, true},
+		{"not negates", "not value > 5", 3, true},
+		{"bang negates", "!(value > 5)", 3, true},
+		{"grouping", "(value - 10) * 2 > 5", 13, true},
+		{"unary minus", "value > -3", -2, true},
+		{"unary minus operand", "-value > 2", -3, true},
+		{"equality", "value == 5", 5, true},
+		{"equality is exact", "value == 3.0000000001", 3, false},
+		{"inequality", "value != 5", 5.5, true},
+		{"equality within tolerance", "value == 5 within 0.01", 5.005, true},
+		{"equality outside tolerance", "value == 5 within 0.01", 5.02, false},
+		{"inequality within tolerance", "value != 5 within 0.01", 5.005, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				match, errEvaluate := evaluateCondition(parseExpr(tt.input), rowValue(tt.value))
+				require.NoError(t, errEvaluate)
+				require.Equal(t, tt.wantMatch, match)
+			},
+		)
+	}
+
+	t.Run(
+		"short-circuit skips right side",
+		func(t *testing.T) {
+			// right side would fail with division by zero if evaluated.
+			match, errEvaluate := evaluateCondition(
+				parseExpr("value > 5 and value / 0 > 1"),
+				rowValue(3),
+			)
+			require.NoError(t, errEvaluate)
+			require.False(t, match)
+
+			match, errEvaluate = evaluateCondition(
+				parseExpr("value > 5 or value / 0 > 1"),
+				rowValue(10),
+			)
+			require.NoError(t, errEvaluate)
+			require.True(t, match)
+		},
+	)
+
+	t.Run(
+		"error - negative tolerance",
+		func(t *testing.T) {
+			_, errEvaluate := evaluateCondition(parseExpr("value == 5 within -1"), rowValue(5))
+			require.Error(t, errEvaluate)
+		},
+	)
+
+	t.Run(
+		"error - non-boolean operand",
+		func(t *testing.T) {
+			_, errEvaluate := evaluateCondition(parseExpr("value and value > 5"), rowValue(3))
+			require.Error(t, errEvaluate)
+		},
+	)
+}
@@ -0,0 +1,74 @@
+package dslalert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunctionCalls(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		value     float64
+		wantMatch bool
+	}{
+		{"abs", "abs(value - 100) > 20", 70, true},
+		{"log", "log(value) > 3", 100, true},
+		{"sqrt", "sqrt(value) == 3", 9, true},
+		{"pow", "pow(value, 2) == 16", 4, true},
+		{"min variadic", "min(value, 10, 3) == 3", 5, true},
+		{"max", "max(value, 10) == 10", 5, true},
+		{"round", "round(value) == 3", 2.6, true},
+		{"round decimals", "round(value, 1) == 2.7", 2.66, true},
+		{"nested", "abs(min(value, 0)) > 1", -2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				match, errEvaluate := evaluateCondition(parseExpr(tt.input), rowValue(tt.value))
+				require.NoError(t, errEvaluate)
+				require.Equal(t, tt.wantMatch, match)
+			},
+		)
+	}
+
+	t.Run(
+		"string round-trip",
+		func(t *testing.T) {
+			expr := parseExpr("abs(value - 100) > 20")
+			require.Equal(t, "(abs((value - 100)) > 20)", expr.String())
+		},
+	)
+
+	t.Run(
+		"error - evaluation domain",
+		func(t *testing.T) {
+			_, errEvaluate := evaluateCondition(parseExpr("log(value) > 3"), rowValue(-1))
+			require.ErrorContains(t, errEvaluate, "function 'log'")
+		},
+	)
+
+	for _, input := range []string{
+		"abs(value, 1) > 2", // arity
+		"min(value) > 2",    // arity
+		"unknown(value) > 2",
+	} {
+		t.Run(
+			"error - "+input,
+			func(t *testing.T) {
+				p := newParser(
+					&paramsNewParser{
+						Lexer: newLexer(strings.NewReader(input)),
+					},
+				)
+
+				require.Nil(t, p.parseExpression(0))
+				require.NotEmpty(t, p.diagnostics)
+			},
+		)
+	}
+}
@@ -0,0 +1,133 @@
+package dslalert
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunctionRegistry(t *testing.T) {
+	rates := map[string]float64{
+		"EUR": 2,
+	}
+
+	registry := NewFunctionRegistry()
+
+	require.NoError(t,
+		registry.Register(
+			"fx_convert",
+			Function{
+				Parameters: []Kind{KindNumber, KindString},
+				Result:     KindNumber,
+				Implementation: func(arguments []any) (any, error) {
+					rate, exists := rates[arguments[1].(string)]
+					if !exists {
+						return nil, errors.New("unknown currency")
+					}
+
+					return arguments[0].(float64) * rate, nil
+				},
+			},
+		),
+	)
+
+	t.Run(
+		"error - invalid registrations",
+		func(t *testing.T) {
+			implementation := func([]any) (any, error) { return 0.0, nil }
+
+			require.Error(t,
+				registry.Register("abs", Function{Implementation: implementation}),
+				"built-ins cannot be redefined",
+			)
+			require.Error(t,
+				registry.Register("fx_convert", Function{Implementation: implementation}),
+				"duplicate name",
+			)
+			require.Error(t,
+				registry.Register("level", Function{Implementation: implementation}),
+				"keyword name",
+			)
+			require.Error(t,
+				registry.Register("1st", Function{Implementation: implementation}),
+				"not an identifier",
+			)
+			require.Error(t,
+				registry.Register("no_body", Function{}),
+			)
+		},
+	)
+
+	input := `
+		criteria "c1" {
+			monitor "amount" {
+				level 1 when fx_convert(value, "EUR") > 10;
+			}
+		}`
+
+	t.Run(
+		"registered function is called",
+		func(t *testing.T) {
+			ast, errs := ParseWithParams(
+				strings.NewReader(input),
+				&ParamsParse{
+					Functions: registry,
+				},
+			)
+			require.Empty(t, errs)
+
+			results, errEvaluate := EvaluateCriteria(
+				ast.Criterias[0],
+				[]string{"amount", "4", "6"},
+			)
+			require.NoError(t, errEvaluate)
+			require.Len(t, results, 1)
+			require.Equal(t, 2, results[0].RowIndex)
+		},
+	)
+
+	t.Run(
+		"error - function not in this configuration",
+		func(t *testing.T) {
+			_, errs := Parse(strings.NewReader(input))
+			require.NotEmpty(t, errs)
+			require.Contains(t, errs[0].Message, "unknown function 'fx_convert'")
+		},
+	)
+
+	t.Run(
+		"error - literal argument kind",
+		func(t *testing.T) {
+			_, errs := ParseWithParams(
+				strings.NewReader(
+					strings.Replace(input, `"EUR"`, `3`, 1),
+				),
+				&ParamsParse{
+					Functions: registry,
+				},
+			)
+			require.NotEmpty(t, errs)
+			require.Contains(t, errs[0].Message, "argument 2 of 'fx_convert' must be string")
+		},
+	)
+
+	t.Run(
+		"error - implementation failure",
+		func(t *testing.T) {
+			_, errEvaluate := evaluateExpression(
+				&ExpressionCall{
+					Name: "fx_convert",
+					Arguments: []Expression{
+						newVariable("value"),
+						newLiteral(stringValue("XXX"), `"XXX"`),
+					},
+					function: registry.functions["fx_convert"],
+				},
+				rowValue(1),
+			)
+			require.ErrorContains(t, errEvaluate, "unknown currency")
+		},
+	)
+}
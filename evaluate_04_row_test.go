@@ -0,0 +1,118 @@
+package dslalert
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnReferences(t *testing.T) {
+	input := `
+		criteria "c1" {
+			monitor "amount" {
+				level 2 when value > col_limit * 1.2;
+				level 1 when value > col_limit;
+			}
+		}`
+
+	ast, errs := Parse(strings.NewReader(input))
+	require.Empty(t, errs)
+
+	dataset := []string{
+		"id,amount,col_limit",
+		"1,50,100",
+		"2,110,100",
+		"3,130,100",
+		"4,10,5",
+	}
+
+	results, errEvaluate := EvaluateCriteria(ast.Criterias[0], dataset)
+	require.NoError(t, errEvaluate)
+	require.Len(t, results, 3)
+
+	require.Equal(t, 2, results[0].RowIndex)
+	require.Equal(t, 1, results[0].RuleLevel)
+	require.Equal(t, 3, results[1].RowIndex)
+	require.Equal(t, 2, results[1].RuleLevel)
+	require.Equal(t, 4, results[2].RowIndex)
+	require.Equal(t, 2, results[2].RuleLevel)
+
+	t.Run(
+		"error - unknown column",
+		func(t *testing.T) {
+			_, errEvaluate := evaluateExpression(
+				parseExpr("value > col_missing"),
+				&rowContext{
+					columns: map[string]int{"amount": 0},
+					record:  []string{"1"},
+				},
+			)
+			require.ErrorContains(t, errEvaluate, "undefined variable 'col_missing'")
+		},
+	)
+
+	t.Run(
+		"row with wrong number of fields is skipped",
+		func(t *testing.T) {
+			results, errEvaluate := EvaluateCriteria(
+				ast.Criterias[0],
+				[]string{
+					"id,amount,col_limit",
+					"1,500",
+					"2,500,100",
+				},
+			)
+			require.NoError(t, errEvaluate)
+			require.Len(t, results, 1)
+			require.Equal(t, 2, results[0].RowIndex)
+		},
+	)
+
+	t.Run(
+		"rows failing are errors of the evaluation, nothing printed",
+		func(t *testing.T) {
+			rows, errDataset := newDatasetRows(
+				"test",
+				[]string{
+					"id,amount,col_limit",
+					"1,500",
+					"2,abc,100",
+					"3,500,100",
+				},
+			)
+			require.NoError(t, errDataset)
+
+			stdout := os.Stdout
+
+			reader, writer, errPipe := os.Pipe()
+			require.NoError(t, errPipe)
+
+			os.Stdout = writer
+
+			results, errs, errEvaluate := compileCriteria(ast.Criterias[0]).evaluateRows(context.Background(), rows)
+
+			os.Stdout = stdout
+			require.NoError(t, writer.Close())
+
+			printed, errRead := io.ReadAll(reader)
+			require.NoError(t, errRead)
+			require.Empty(t, string(printed))
+
+			require.NoError(t, errEvaluate)
+			require.Len(t, results, 1)
+			require.Equal(t, 3, results[0].RowIndex)
+
+			require.Len(t, errs, 3)
+			require.Equal(t, "row 1, criteria 'c1': 2 fields, the header has 3", errs[0].Error())
+			require.Equal(t, 2, errs[1].RowIndex)
+			require.Equal(t, "amount", errs[1].MonitorName)
+			require.Equal(t, 2, errs[1].RuleLevel)
+			require.Equal(t, 1, errs[2].RuleLevel)
+			require.ErrorContains(t, errs.Err(), `cannot order string "abc" and number 120`)
+		},
+	)
+}
@@ -0,0 +1,191 @@
+package dslalert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCell(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Value
+	}{
+		{"12.5", numberValue(12.5)},
+		{" 3 ", numberValue(3)},
+		{"FAILED", stringValue("FAILED")},
+		{`"42"`, stringValue("42")},
+		{"true", boolValue(true)},
+		{"FALSE", boolValue(false)},
+		{"1", numberValue(1)},
+		{"", nullValue()},
+		{"null", nullValue()},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.raw,
+			func(t *testing.T) {
+				require.Equal(t, tt.want, parseCell(tt.raw))
+			},
+		)
+	}
+}
+
+func TestEvaluateTypedValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		value     Value
+		wantMatch bool
+	}{
+		{"string equality", `value == "FAILED"`, stringValue("FAILED"), true},
+		{"string inequality", `value != "FAILED"`, stringValue("OK"), true},
+		{"string ordering", `value < "b"`, stringValue("a"), true},
+		{"string concatenation", `value + "_X" == "A_X"`, stringValue("A"), true},
+		{"bool literal", `value == true`, boolValue(true), true},
+		{"bool as condition", `value`, boolValue(true), true},
+		{"bool negation", `not value`, boolValue(false), true},
+		{"null equality", `value == null`, nullValue(), true},
+		{"mixed kinds never equal", `value == "5"`, numberValue(5), false},
+		{"mixed kinds differ", `value != "5"`, numberValue(5), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				match, errEvaluate := evaluateCondition(
+					parseExpr(tt.input),
+					&rowContext{
+						value: tt.value,
+					},
+				)
+				require.NoError(t, errEvaluate)
+				require.Equal(t, tt.wantMatch, match)
+			},
+		)
+	}
+
+	for _, input := range []string{
+		`value > "5"`,  // ordering across kinds
+		`value + true`, // arithmetic on bool
+		`-"x" == 1`,    // unary minus on string
+		`value and true`,
+	} {
+		t.Run(
+			"error - "+input,
+			func(t *testing.T) {
+				_, errEvaluate := evaluateCondition(parseExpr(input), rowValue(5))
+				require.Error(t, errEvaluate)
+			},
+		)
+	}
+
+	t.Run(
+		"monitor on a string column",
+		func(t *testing.T) {
+			ast, errs := Parse(
+				strings.NewReader(`
+				criteria "jobs" {
+					monitor "status" {
+						level 2 when value == "FAILED" and retried;
+						level 1 when value == "FAILED";
+					}
+				}`),
+			)
+			require.Empty(t, errs)
+
+			results, errEvaluate := EvaluateCriteria(
+				ast.Criterias[0],
+				[]string{
+					"job,status,retried",
+					"1,OK,false",
+					"2,FAILED,false",
+					"3,FAILED,true",
+				},
+			)
+			require.NoError(t, errEvaluate)
+			require.Len(t, results, 2)
+			require.Equal(t, 1, results[0].RuleLevel)
+			require.Equal(t, "FAILED", results[0].ValueCurrent)
+			require.Equal(t, 2, results[1].RuleLevel)
+		},
+	)
+}
+
+func TestEvaluateNaN(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantMatch bool
+	}{
+		{"value > 5", false},
+		{"value >= 5", false},
+		{"value < 5", false},
+		{"value <= 5", false},
+		{"5 <= value", false},
+		{"value == value", false},
+		{"value != 5", true},
+		{"value between 1 and 10", false},
+		{"value not between 1 and 10", true},
+		{"not (value > 5)", true},
+		{"abs(value) >= 5", false},
+		{"abs(value) <= 5", false},
+		{"value * 2 < 5", false},
+	}
+
+	for ix, tt := range tests {
+		t.Run(
+			fmt.Sprintf("%d. %s", ix+1, tt.input),
+			func(t *testing.T) {
+				row := &rowContext{
+					value: parseCell("NaN"),
+				}
+
+				match, errEvaluate := evaluateCondition(parseExpr(tt.input), row)
+				require.NoError(t, errEvaluate)
+				require.Equal(t, tt.wantMatch, match, "tree")
+
+				optimized := optimizeCondition(parseExpr(tt.input))
+
+				match, errEvaluate = compileCondition(optimized, nil)(row)
+				require.NoError(t, errEvaluate)
+				require.Equal(t, tt.wantMatch, match, "compiled")
+
+				match, errEvaluate = compileCondition(optimized, checkCondition(optimized))(row)
+				require.NoError(t, errEvaluate)
+				require.Equal(t, tt.wantMatch, match, "compiled checked")
+			},
+		)
+	}
+
+	t.Run(
+		"NaN cell matches no level",
+		func(t *testing.T) {
+			ast, errs := Parse(
+				strings.NewReader(`
+				criteria "c1" {
+					monitor "amount" {
+						level 2 when value >= 5;
+						level 1 when value <= 5;
+					}
+				}`),
+			)
+			require.Empty(t, errs)
+
+			results, errEvaluate := EvaluateCriteria(
+				ast.Criterias[0],
+				[]string{
+					"id,amount",
+					"1,NaN",
+					"2,7",
+				},
+			)
+			require.NoError(t, errEvaluate)
+			require.Len(t, results, 1)
+			require.Equal(t, 2, results[0].RuleLevel)
+		},
+	)
+}
@@ -0,0 +1,82 @@
+package dslalert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringMatching(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		value     string
+		wantMatch bool
+	}{
+		{"contains", `value contains "timeout"`, "read timeout after 5s", true},
+		{"contains miss", `value contains "timeout"`, "refused", false},
+		{"startswith", `value startswith "ERR-"`, "ERR-42", true},
+		{"endswith", `value endswith ".csv"`, "export.csv", true},
+		{"matches", `value matches "^ERR-[0-9]+$"`, "ERR-42", true},
+		{"matches miss", `value matches "^ERR-[0-9]+$"`, "ERR-4x", false},
+		{"matches raw string", "value matches `^ERR-\\d+$`", "ERR-7", true},
+		{"combined", `value startswith "ERR" and not value endswith "0"`, "ERR-1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				match, errEvaluate := evaluateCondition(
+					parseExpr(tt.input),
+					&rowContext{
+						value: stringValue(tt.value),
+					},
+				)
+				require.NoError(t, errEvaluate)
+				require.Equal(t, tt.wantMatch, match)
+			},
+		)
+	}
+
+	t.Run(
+		"string output",
+		func(t *testing.T) {
+			require.Equal(t,
+				`((value contains "a") or (value matches "^b"))`,
+				parseExpr(`value contains "a" or value matches "^b"`).String(),
+			)
+		},
+	)
+
+	t.Run(
+		"error - non-string operand",
+		func(t *testing.T) {
+			_, errEvaluate := evaluateCondition(parseExpr(`value contains "1"`), rowValue(1))
+			require.ErrorContains(t, errEvaluate, "needs two strings")
+		},
+	)
+
+	for _, input := range []string{
+		`value matches "[0-9"`,
+		`value matches value`,
+	} {
+		t.Run(
+			"error - parse "+input,
+			func(t *testing.T) {
+				p := newParser(
+					&paramsNewParser{
+						Lexer: newLexer(strings.NewReader(input)),
+					},
+				)
+
+				require.Nil(t, p.parseExpression(0))
+				require.NotEmpty(t, p.diagnostics)
+				require.ErrorIs(t, p.diagnostics[0], CodeInvalidPattern)
+				require.Equal(t, 15, p.diagnostics[0].Start.Column)
+				require.Equal(t, 1+len(input), p.diagnostics[0].End.Column)
+			},
+		)
+	}
+}
@@ -0,0 +1,73 @@
+package dslalert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMembership(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		value     Value
+		wantMatch bool
+	}{
+		{"in", "value in [3, 5, 7]", numberValue(5), true},
+		{"in miss", "value in [3, 5, 7]", numberValue(4), false},
+		{"not in", "value not in [3, 5, 7]", numberValue(4), true},
+		{"in strings", `value in ["FAILED", "ABORTED"]`, stringValue("ABORTED"), true},
+		{"in empty", "value in []", numberValue(1), false},
+		{"in computed", "value in [1 + 1, 2 * 2]", numberValue(4), true},
+		{"between inclusive lower", "value between 10 and 20", numberValue(10), true},
+		{"between inclusive upper", "value between 10 and 20", numberValue(20), true},
+		{"between outside", "value between 10 and 20", numberValue(21), false},
+		{"not between", "value not between 10 and 20", numberValue(21), true},
+		{"between then and", "value between 10 and 20 and value != 15", numberValue(15), false},
+		{"between strings", `value between "a" and "c"`, stringValue("b"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				match, errEvaluate := evaluateCondition(
+					parseExpr(tt.input),
+					&rowContext{
+						value: tt.value,
+					},
+				)
+				require.NoError(t, errEvaluate)
+				require.Equal(t, tt.wantMatch, match)
+			},
+		)
+	}
+
+	t.Run(
+		"string output",
+		func(t *testing.T) {
+			tests := []struct {
+				input string
+				want  string
+			}{
+				{"value not in [1, 2]", "(value not in [1, 2])"},
+				{"value between 1 + 1 and 5 or value in []", "((value between (1 + 1) and 5) or (value in []))"},
+				{"value not between 1 and 2", "(value not between 1 and 2)"},
+			}
+
+			for _, tt := range tests {
+				expr := parseExpr(tt.input)
+				require.Equal(t, tt.want, expr.String())
+				require.Equal(t, tt.want, parseExpr(expr.String()).String())
+			}
+		},
+	)
+
+	t.Run(
+		"error - in without list",
+		func(t *testing.T) {
+			_, errEvaluate := evaluateCondition(parseExpr("value in 5"), rowValue(5))
+			require.ErrorContains(t, errEvaluate, "must be list")
+		},
+	)
+}
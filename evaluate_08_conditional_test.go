@@ -0,0 +1,88 @@
+package dslalert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditional(t *testing.T) {
+	row := func(value float64, weekend string) *rowContext {
+		return &rowContext{
+			columns: map[string]int{"weekend": 0},
+			record:  []string{weekend},
+			value:   numberValue(value),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		row       *rowContext
+		wantMatch bool
+	}{
+		{"if then", "value > (if weekend then 50 else 100)", row(60, "true"), true},
+		{"if else", "value > (if weekend then 50 else 100)", row(60, "false"), false},
+		{
+			"case first branch",
+			"value * case when weekend then 2 when value > 10 then 3 else 1 end > 100",
+			row(60, "true"),
+			true,
+		},
+		{
+			"case second branch",
+			"value * case when weekend then 2 when value > 10 then 3 else 1 end > 150",
+			row(60, "false"),
+			true,
+		},
+		{
+			"case without else is null",
+			"case when weekend then 1 end == null",
+			row(1, "false"),
+			true,
+		},
+		{
+			"lazy branches",
+			"if value == 0 then false else 10 / value > 1",
+			row(0, "true"),
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				match, errEvaluate := evaluateCondition(parseExpr(tt.input), tt.row)
+				require.NoError(t, errEvaluate)
+				require.Equal(t, tt.wantMatch, match)
+			},
+		)
+	}
+
+	t.Run(
+		"string output",
+		func(t *testing.T) {
+			expr := parseExpr("case when value > 1 then 2 else 3 end")
+			require.Equal(t,
+				"(if (value > 1) then 2 else 3)",
+				expr.String(),
+			)
+			require.Equal(t,
+				expr.String(),
+				parseExpr(expr.String()).String(),
+			)
+		},
+	)
+
+	t.Run(
+		"error - non-boolean condition",
+		func(t *testing.T) {
+			_, errEvaluate := evaluateCondition(
+				parseExpr("if value then true else false"),
+				rowValue(1),
+			)
+			require.ErrorContains(t, errEvaluate, "must be bool")
+		},
+	)
+}
@@ -0,0 +1,133 @@
+package dslalert
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var _conditionsCompile = []string{
+	"value > 5",
+	"value >= 5 and value < 100 or value == 0",
+	"not (value between 0 and 10)",
+	"value not between -1 and 1",
+	"value in [1, 2, 3.5]",
+	"value not in [limit, 2]",
+	"value == limit within 0.5",
+	"value != 7",
+	"value * 2 + 1 > limit / 4",
+	"-value < -3",
+	"abs(value - 100) > 10",
+	"round(value, 1) == 3.5",
+	"value > (if weekend then 50 else 100)",
+	`name startswith "ab" and value > 0`,
+	`name + "!" == "abc!"`,
+	`name > "abb"`,
+	"value / 0 > 1",
+	"value > name",
+	"value and true",
+	"missing > 1",
+	"value + 1",
+}
+
+func TestCompileEvaluatesLikeTree(t *testing.T) {
+	row := func(value string) *rowContext {
+		return &rowContext{
+			columns: map[string]int{"limit": 0, "weekend": 1, "name": 2},
+			record:  []string{"20", "true", "abc"},
+			value:   parseCell(value),
+		}
+	}
+
+	for ix, condition := range _conditionsCompile {
+		for _, isChecked := range []bool{false, true} {
+			t.Run(
+				fmt.Sprintf("%d. %s, checked %t", ix+1, condition, isChecked),
+				func(t *testing.T) {
+					expression := parseExpr(condition)
+
+					var info *TypeInfo
+					if isChecked {
+						info = checkCondition(expression)
+					}
+
+					compiled := compileCondition(expression, info)
+
+					for _, cell := range []string{"-10", "0", "3.5", "7", "20", "NaN", "abc", "", "true"} {
+						matchTree, errTree := evaluateCondition(expression, row(cell))
+						matchCompiled, errCompiled := compiled(row(cell))
+
+						if errTree != nil {
+							require.EqualError(t, errCompiled, errTree.Error(), cell)
+
+							continue
+						}
+
+						require.NoError(t, errCompiled, cell)
+						require.Equal(t, matchTree, matchCompiled, cell)
+					}
+				},
+			)
+		}
+	}
+}
+
+func TestCompileAllocations(t *testing.T) {
+	row := &rowContext{
+		columns: map[string]int{"limit": 0},
+		record:  []string{"20"},
+		value:   numberValue(42),
+	}
+
+	for ix, condition := range []string{
+		"value > 5 and value <= 100",
+		"value between 10 and 50 or value in [1, 2, 3]",
+		"value * 2 - 1 > limit",
+		"not (value == 40 within 5)",
+	} {
+		t.Run(
+			fmt.Sprintf("%d. %s", ix+1, condition),
+			func(t *testing.T) {
+				expression := parseExpr(condition)
+
+				compiled := compileCondition(expression, checkCondition(expression))
+
+				allocations := testing.AllocsPerRun(
+					100,
+					func() {
+						_, _ = compiled(row)
+					},
+				)
+
+				require.Zero(t, allocations)
+			},
+		)
+	}
+}
+
+const _conditionBenchmark = "value > 100 and value <= 1000 or value between 10 and 20 or value in [1, 2, 3]"
+
+func BenchmarkEvaluateTree(b *testing.B) {
+	expression := parseExpr(_conditionBenchmark)
+	row := rowValue(15)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, _ = evaluateCondition(expression, row)
+	}
+}
+
+func BenchmarkEvaluateCompiled(b *testing.B) {
+	expression := parseExpr(_conditionBenchmark)
+
+	compiled := compileCondition(expression, checkCondition(expression))
+	row := rowValue(15)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, _ = compiled(row)
+	}
+}
@@ -0,0 +1,329 @@
+package dslalert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// bytecodeCondition compiles a single condition, returning a VM running it.
+func bytecodeCondition(t testing.TB, condition string) (*VM, int) {
+	c := newBytecodeCompiler()
+
+	entry, errCompile := c.condition(parseExpr(condition))
+	require.NoError(t, errCompile)
+
+	vm, errVM := NewVM(c.result, &ParamsVM{})
+	require.NoError(t, errVM)
+
+	return vm, entry
+}
+
+func TestBytecodeEvaluatesLikeTree(t *testing.T) {
+	row := func(value string) *rowContext {
+		return &rowContext{
+			columns: map[string]int{"limit": 0, "weekend": 1, "name": 2},
+			record:  []string{"20", "true", "abc"},
+			value:   parseCell(value),
+		}
+	}
+
+	for ix, condition := range append(_conditionsCompile, `name matches "^a.c$" or value > 1`) {
+		t.Run(
+			fmt.Sprintf("%d. %s", ix+1, condition),
+			func(t *testing.T) {
+				expression := parseExpr(condition)
+				vm, entry := bytecodeCondition(t, condition)
+
+				for _, cell := range []string{"-10", "0", "3.5", "7", "20", "NaN", "abc", "", "true"} {
+					matchTree, errTree := evaluateCondition(expression, row(cell))
+					matchVM, errVM := vm.run(entry, row(cell))
+
+					if errTree != nil {
+						require.Error(t, errVM, cell)
+
+						continue
+					}
+
+					require.NoError(t, errVM, cell)
+					require.Equal(t, matchTree, matchVM, cell)
+				}
+			},
+		)
+	}
+}
+
+const _inputBytecode = `
+	criteria "limits" {
+		monitor "amount" {
+			level 1 when value > limit;
+			level 3 when value > limit * 2 or abs(value) > 1000;
+			level 2 when value between limit and limit * 2 and label startswith "x";
+		}
+	}
+
+	criteria "labels" {
+		monitor "label" {
+			level 1 when value matches "^x[0-9]+$";
+		}
+	}`
+
+var _datasetBytecode = []string{
+	"amount,limit,label",
+	"5,10,x1",
+	"15,10,x2",
+	"15,10,y3",
+	"25,10,x4",
+	"-2000,10,z5",
+}
+
+func TestBytecodeVM(t *testing.T) {
+	ast, errs := Parse(strings.NewReader(_inputBytecode))
+	require.Empty(t, errs)
+
+	bytecode, errCompile := CompileBytecode(ast)
+	require.NoError(t, errCompile)
+
+	t.Run(
+		"1. results match EvaluateCriteria",
+		func(t *testing.T) {
+			vm, errVM := NewVM(bytecode, &ParamsVM{})
+			require.NoError(t, errVM)
+
+			for _, criteria := range ast.Criterias {
+				want, errEvaluate := EvaluateCriteria(criteria, _datasetBytecode)
+				require.NoError(t, errEvaluate)
+
+				got, errEvaluate := vm.EvaluateCriteria(criteria.Name, _datasetBytecode)
+				require.NoError(t, errEvaluate)
+				require.Equal(t, want, got, criteria.Name)
+			}
+		},
+	)
+
+	t.Run(
+		"2. round trip through MarshalBinary",
+		func(t *testing.T) {
+			data, errMarshal := bytecode.MarshalBinary()
+			require.NoError(t, errMarshal)
+
+			loaded, errUnmarshal := UnmarshalBytecode(data, nil)
+			require.NoError(t, errUnmarshal)
+			require.Equal(t, bytecode.Disassemble(), loaded.Disassemble())
+			require.Equal(t, bytecode.stackMaximum, loaded.stackMaximum)
+
+			vm, errVM := NewVM(loaded, &ParamsVM{})
+			require.NoError(t, errVM)
+
+			want, errEvaluate := EvaluateCriteria(ast.Criterias[0], _datasetBytecode)
+			require.NoError(t, errEvaluate)
+
+			got, errEvaluate := vm.EvaluateCriteria("limits", _datasetBytecode)
+			require.NoError(t, errEvaluate)
+			require.Equal(t, want, got)
+		},
+	)
+
+	t.Run(
+		"3. disassembly",
+		func(t *testing.T) {
+			disassembly := bytecode.Disassemble()
+
+			require.True(
+				t,
+				strings.HasPrefix(
+					disassembly,
+					"criteria 'limits', monitor 'amount', level 3:\n0000  VALUE\n",
+				),
+				disassembly,
+			)
+			require.Contains(t, disassembly, "0001  COLUMN                0 (limit)")
+			require.Contains(t, disassembly, "0013  CALL                  0 (abs/1)")
+			require.Contains(t, disassembly, "0062  MATCHES               0 (^x[0-9]+$)")
+			require.Contains(t, disassembly, "criteria 'labels', monitor 'label', level 1:")
+		},
+	)
+
+	t.Run(
+		"4. hash follows the formatted configuration",
+		func(t *testing.T) {
+			reformatted, errs := Parse(strings.NewReader(Format(ast)))
+			require.Empty(t, errs)
+
+			require.Equal(t, HashConfiguration(ast), HashConfiguration(reformatted))
+			require.Len(t, HashConfiguration(ast), 64)
+		},
+	)
+
+	t.Run(
+		"error - unknown criteria",
+		func(t *testing.T) {
+			vm, errVM := NewVM(bytecode, &ParamsVM{})
+			require.NoError(t, errVM)
+
+			_, errEvaluate := vm.EvaluateCriteria("missing", _datasetBytecode)
+			require.Error(t, errEvaluate)
+		},
+	)
+
+	t.Run(
+		"error - stack limit",
+		func(t *testing.T) {
+			_, errVM := NewVM(
+				bytecode,
+				&ParamsVM{
+					StackMaximum: 1,
+				},
+			)
+			require.ErrorIs(t, errVM, ErrExecutionLimit)
+		},
+	)
+
+	t.Run(
+		"error - function not in registry",
+		func(t *testing.T) {
+			registry := NewFunctionRegistry()
+			require.NoError(
+				t,
+				registry.Register(
+					"twice",
+					Function{
+						Parameters: []Kind{KindNumber},
+						Result:     KindNumber,
+						Implementation: func(arguments []any) (any, error) {
+							return arguments[0].(float64) * 2, nil
+						},
+					},
+				),
+			)
+
+			withHost, errs := ParseWithParams(
+				strings.NewReader(`criteria "c" { monitor "m" { level 1 when twice(value) > 4; } }`),
+				&ParamsParse{
+					Functions: registry,
+				},
+			)
+			require.Empty(t, errs)
+
+			compiled, errCompile := CompileBytecode(withHost)
+			require.NoError(t, errCompile)
+
+			data, errMarshal := compiled.MarshalBinary()
+			require.NoError(t, errMarshal)
+
+			_, errUnmarshal := UnmarshalBytecode(data, nil)
+			require.Error(t, errUnmarshal)
+
+			loaded, errUnmarshal := UnmarshalBytecode(data, registry)
+			require.NoError(t, errUnmarshal)
+
+			vm, errVM := NewVM(loaded, &ParamsVM{})
+			require.NoError(t, errVM)
+
+			results, errEvaluate := vm.EvaluateCriteria("c", []string{"m", "1", "3"})
+			require.NoError(t, errEvaluate)
+			require.Len(t, results, 1)
+			require.Equal(t, 2, results[0].RowIndex)
+		},
+	)
+}
+
+func TestBytecodeLimits(t *testing.T) {
+	t.Run(
+		"1. step limit",
+		func(t *testing.T) {
+			c := newBytecodeCompiler()
+
+			entry, errCompile := c.condition(parseExpr("value > 1 and value > 2 and value > 3"))
+			require.NoError(t, errCompile)
+
+			vm, errVM := NewVM(
+				c.result,
+				&ParamsVM{
+					StepsMaximum: 6,
+				},
+			)
+			require.NoError(t, errVM)
+
+			_, errRun := vm.run(entry, rowValue(10))
+			require.ErrorIs(t, errRun, ErrExecutionLimit)
+
+			_, errRun = vm.run(entry, rowValue(0))
+			require.NoError(t, errRun, "short-circuit stays within the limit")
+		},
+	)
+
+	t.Run(
+		"2. nil params take the defaults",
+		func(t *testing.T) {
+			c := newBytecodeCompiler()
+
+			entry, errCompile := c.condition(parseExpr("value > 1"))
+			require.NoError(t, errCompile)
+
+			vm, errVM := NewVM(c.result, nil)
+			require.NoError(t, errVM)
+			require.Equal(t, stepsMaximumDefault, vm.stepsMaximum)
+
+			match, errRun := vm.run(entry, rowValue(10))
+			require.NoError(t, errRun)
+			require.True(t, match)
+		},
+	)
+
+	t.Run(
+		"3. verification rejects corrupt code",
+		func(t *testing.T) {
+			ast, errs := Parse(strings.NewReader(_inputBytecode))
+			require.Empty(t, errs)
+
+			bytecode, errCompile := CompileBytecode(ast)
+			require.NoError(t, errCompile)
+
+			corrupt := []func(b *Bytecode){
+				func(b *Bytecode) { b.code[0] = 0xff },
+				func(b *Bytecode) { b.code = b.code[:len(b.code)-2] },
+				func(b *Bytecode) { b.code[0] = byte(opAdd) },
+				func(b *Bytecode) { b.constants = nil },
+				func(b *Bytecode) { b.criterias[0].monitors[0].rules[0].entry = 1 },
+			}
+
+			for ix, change := range corrupt {
+				copied := *bytecode
+				copied.code = append([]byte(nil), bytecode.code...)
+				copied.criterias = []bytecodeCriteria{
+					{
+						name: "limits",
+						monitors: []bytecodeMonitor{
+							{
+								columnName: "amount",
+								rules:      append([]bytecodeRule(nil), bytecode.criterias[0].monitors[0].rules...),
+							},
+						},
+					},
+				}
+
+				change(&copied)
+
+				data, errMarshal := copied.MarshalBinary()
+				require.NoError(t, errMarshal)
+
+				_, errUnmarshal := UnmarshalBytecode(data, nil)
+				require.Error(t, errUnmarshal, ix+1)
+			}
+		},
+	)
+}
+
+func BenchmarkEvaluateBytecode(b *testing.B) {
+	vm, entry := bytecodeCondition(b, _conditionBenchmark)
+	row := rowValue(15)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, _ = vm.run(entry, row)
+	}
+}
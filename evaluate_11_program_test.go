@@ -0,0 +1,175 @@
+package dslalert
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const _inputProgram = `
+	criteria "amounts" {
+		monitor "amount" {
+			level 1 when value > limit;
+			level 3 when value > limit * 3;
+			level 2 when value > limit * 2;
+		}
+	}
+
+	criteria "latency" {
+		monitor "latency" {
+			level 2 when 500 < value;
+			level 1 when value between 100 and 500;
+		}
+	}`
+
+var _datasetProgram = []string{
+	"amount,limit,latency",
+	"5,10,50",
+	"15,10,150",
+	"25,10,650",
+	"35,10,20",
+	"x,10,",
+}
+
+func TestProgram(t *testing.T) {
+	ast, errs := Parse(strings.NewReader(_inputProgram))
+	require.Empty(t, errs)
+
+	formatted := Format(ast)
+
+	program, errCompile := Compile(ast)
+	require.NoError(t, errCompile)
+	require.Equal(t, formatted, Format(ast), "configuration unchanged")
+
+	require.Equal(t, []string{"amounts", "latency"}, program.CriteriaNames())
+
+	t.Run(
+		"1. results match EvaluateCriteria",
+		func(t *testing.T) {
+			for _, criteria := range ast.Criterias {
+				want, errEvaluate := EvaluateCriteria(criteria, _datasetProgram)
+				require.NoError(t, errEvaluate)
+				require.NotEmpty(t, want)
+
+				got, errEvaluate := program.EvaluateCriteria(criteria.Name, _datasetProgram)
+				require.NoError(t, errEvaluate)
+				require.Equal(t, want, got, criteria.Name)
+			}
+		},
+	)
+
+	t.Run(
+		"2. concurrent evaluation while the configuration changes",
+		func(t *testing.T) {
+			want := make(map[string]EvaluationResults)
+
+			for _, name := range program.CriteriaNames() {
+				results, errEvaluate := program.EvaluateCriteria(name, _datasetProgram)
+				require.NoError(t, errEvaluate)
+
+				want[name] = results
+			}
+
+			var wg sync.WaitGroup
+
+			wg.Add(1 + 16)
+
+			go func() {
+				defer wg.Done()
+
+				for range 100 {
+					for _, monitor := range ast.Criterias[0].Monitors {
+						monitor.Rules[0], monitor.Rules[1] = monitor.Rules[1], monitor.Rules[0]
+						monitor.Rules[0].Level++
+					}
+				}
+			}()
+
+			got := make([][]EvaluationResults, 16)
+
+			for ix := range got {
+				go func() {
+					defer wg.Done()
+
+					for range 50 {
+						for _, name := range program.CriteriaNames() {
+							results, errEvaluate := program.EvaluateCriteria(name, _datasetProgram)
+							if errEvaluate != nil {
+								panic(errEvaluate)
+							}
+
+							got[ix] = append(got[ix], results)
+						}
+					}
+				}()
+			}
+
+			wg.Wait()
+
+			for ix, resultsWorker := range got {
+				require.Len(t, resultsWorker, 100, ix)
+
+				for ixResults, results := range resultsWorker {
+					require.Equal(
+						t,
+						want[program.CriteriaNames()[ixResults%2]],
+						results,
+						fmt.Sprintf("worker %d, evaluation %d", ix, ixResults),
+					)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"error - unknown criteria",
+		func(t *testing.T) {
+			_, errEvaluate := program.EvaluateCriteria("missing", _datasetProgram)
+			require.Error(t, errEvaluate)
+		},
+	)
+}
+
+func TestCompile(t *testing.T) {
+	t.Run(
+		"error - nil configuration",
+		func(t *testing.T) {
+			_, errCompile := Compile(nil)
+			require.Error(t, errCompile)
+		},
+	)
+
+	t.Run(
+		"error - configuration not valid",
+		func(t *testing.T) {
+			ast, errs := Parse(strings.NewReader(_inputProgram))
+			require.Empty(t, errs)
+
+			ast.Criterias[1].Name = ast.Criterias[0].Name
+
+			_, errCompile := Compile(ast)
+			require.ErrorIs(t, errCompile, CodeDuplicateCriteria)
+		},
+	)
+
+	t.Run(
+		"error - node tolerant parsing could not read",
+		func(t *testing.T) {
+			ast, errs := ParseWithParams(
+				strings.NewReader(
+					strings.Replace(_inputProgram, "value > limit;", "value > ;", 1),
+				),
+				&ParamsParse{
+					IsTolerant: true,
+				},
+			)
+			require.True(t, errs.HasErrors())
+
+			_, errCompile := Compile(ast)
+			require.ErrorContains(t, errCompile, "tolerant parsing could not read")
+		},
+	)
+}
@@ -0,0 +1,115 @@
+package dslalert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateConfiguration(t *testing.T) {
+	ast, errs := Parse(strings.NewReader(_inputProgram))
+	require.Empty(t, errs)
+
+	source := strings.Join(_datasetProgram, "\n") + "\n"
+
+	for ix, workers := range []int{0, 1, 2, 8} {
+		t.Run(
+			fmt.Sprintf("%d. workers %d", ix+1, workers),
+			func(t *testing.T) {
+				results, errEvaluate := EvaluateConfigurationWithParams(
+					context.Background(),
+					ast,
+					strings.NewReader(source),
+					&ParamsEvaluate{
+						Workers: workers,
+					},
+				)
+				require.NoError(t, errEvaluate)
+				require.Len(t, results, 2)
+
+				for ixCriteria, criteria := range ast.Criterias {
+					want, errEvaluate := EvaluateCriteria(criteria, _datasetProgram)
+					require.NoError(t, errEvaluate)
+
+					require.Equal(t, criteria.Name, results[ixCriteria].CriteriaName)
+					require.Equal(t, want, results[ixCriteria].Results)
+					require.Equal(t, want, results.Criteria(criteria.Name))
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"5. rows failing are reported per criteria",
+		func(t *testing.T) {
+			results, errEvaluate := EvaluateConfiguration(
+				context.Background(),
+				ast,
+				strings.NewReader(source+"1,2\n"),
+			)
+			require.NoError(t, errEvaluate)
+
+			// 'x' orders with no number, '' is null.
+			require.Len(t, results[0].Errors, 4)
+			require.Equal(t, 5, results[0].Errors[0].RowIndex)
+			require.Equal(t, "amount", results[0].Errors[0].MonitorName)
+			require.Equal(t, 3, results[0].Errors[0].RuleLevel)
+			require.Equal(t, 6, results[0].Errors[3].RowIndex)
+			require.Empty(t, results[0].Errors[3].MonitorName, "line skipped")
+
+			require.Len(t, results[1].Errors, 3)
+			require.Equal(t, "latency", results[1].Errors[0].MonitorName)
+
+			require.ErrorContains(t, results.Err(), "row 6, criteria 'latency': 2 fields, the header has 3")
+		},
+	)
+
+	t.Run(
+		"6. lines ending in CRLF",
+		func(t *testing.T) {
+			results, errEvaluate := EvaluateConfiguration(
+				context.Background(),
+				ast,
+				strings.NewReader(strings.ReplaceAll(source, "\n", "\r\n")),
+			)
+			require.NoError(t, errEvaluate)
+
+			want, errEvaluate := EvaluateCriteria(ast.Criterias[1], _datasetProgram)
+			require.NoError(t, errEvaluate)
+			require.Equal(t, want, results.Criteria("latency"))
+		},
+	)
+
+	t.Run(
+		"error - context done",
+		func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, errEvaluate := EvaluateConfiguration(ctx, ast, strings.NewReader(source))
+			require.ErrorIs(t, errEvaluate, context.Canceled)
+		},
+	)
+
+	t.Run(
+		"error - empty dataset",
+		func(t *testing.T) {
+			_, errEvaluate := EvaluateConfiguration(context.Background(), ast, strings.NewReader(""))
+			require.Error(t, errEvaluate)
+
+			_, errEvaluate = EvaluateConfiguration(context.Background(), ast, nil)
+			require.Error(t, errEvaluate)
+		},
+	)
+
+	t.Run(
+		"error - configuration not valid",
+		func(t *testing.T) {
+			_, errEvaluate := EvaluateConfiguration(context.Background(), nil, strings.NewReader(source))
+			require.Error(t, errEvaluate)
+		},
+	)
+}
@@ -3,6 +3,7 @@ package dslalert
 import (
 	"fmt"
 	"runtime"
+	"slices"
 	"strconv"
 )
 
@@ -19,6 +20,87 @@ func traceExit() {
 	}
 }
 
+func isKeyword(word string) bool {
+	return slices.Contains(_keywords, word)
+}
+
+// suggestKeyword returns the keyword closest to a misspelled word, or ""
+// when none is close enough to be a likely typo.
+func suggestKeyword(word string) string {
+	var result string
+
+	distanceBest := 3 // at most two edits
+
+	for _, keyword := range _keywords {
+		distance := editDistance(word, keyword)
+
+		if distance == 0 || 2*distance >= len(keyword) {
+			continue
+		}
+
+		if distance < distanceBest {
+			result, distanceBest = keyword, distance
+		}
+	}
+
+	return result
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+
+	for ix := range previous {
+		previous[ix] = ix
+	}
+
+	for ixA := 1; ixA <= len(runesA); ixA++ {
+		current[0] = ixA
+
+		for ixB := 1; ixB <= len(runesB); ixB++ {
+			cost := 1
+			if runesA[ixA-1] == runesB[ixB-1] {
+				cost = 0
+			}
+
+			current[ixB] = min(
+				previous[ixB]+1,
+				current[ixB-1]+1,
+				previous[ixB-1]+cost,
+			)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(runesB)]
+}
+
+// isIdentifier mirrors the lexer's IsIdentRune rule.
+func isIdentifier(word string) bool {
+	if len(word) == 0 {
+		return false
+	}
+
+	for ix, ch := range word {
+		if !isIdentifierRune(ch, ix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isIdentifierRune(ch rune, ix int) bool {
+	return (ch >= 'a' && ch <= 'z') ||
+		(ch >= 'A' && ch <= 'Z') ||
+		(ch == '_') ||
+		(ix > 0 && ch >= '0' && ch <= '9')
+}
+
 func isComparisonOperator(operator string) bool {
 	switch operator {
 	case ">", ">=", "<", "<=", "==", "!=":
@@ -29,6 +111,36 @@ func isComparisonOperator(operator string) bool {
 	}
 }
 
+func isOrderingOperator(operator string) bool {
+	switch operator {
+	case ">", ">=", "<", "<=":
+		return true
+
+	default:
+		return false
+	}
+}
+
+func isEqualityOperator(operator string) bool {
+	switch operator {
+	case "==", "!=":
+		return true
+
+	default:
+		return false
+	}
+}
+
+func isStringOperator(operator string) bool {
+	switch operator {
+	case _dslContains, _dslStartsWith, _dslEndsWith, _dslMatches:
+		return true
+
+	default:
+		return false
+	}
+}
+
 func isArithmeticOperator(operator string) bool {
 	switch operator {
 	case "+", "-", "*", "/":
@@ -39,6 +151,26 @@ func isArithmeticOperator(operator string) bool {
 	}
 }
 
+func isPrefixOperator(operator string) bool {
+	switch operator {
+	case "-", "+", "!", _dslNot:
+		return true
+
+	default:
+		return false
+	}
+}
+
+func isLogicalOperator(operator string) bool {
+	switch operator {
+	case _dslAnd, _dslOr:
+		return true
+
+	default:
+		return false
+	}
+}
+
 func toFloat64(val any) (float64, bool) {
 	switch v := val.(type) {
 	case float64:
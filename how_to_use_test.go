@@ -1,6 +1,7 @@
 package dslalert_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	dslalert "test"
@@ -66,3 +67,51 @@ func TestHowToUse(t *testing.T) {
 		)
 	}
 }
+
+func TestHowToUseConfiguration(t *testing.T) {
+	inputCriteria := `
+			criteria "c1" {
+				monitor "col1" {
+					level 1 when value > 5;
+					level 2 when value > 10;
+				}
+			}
+			criteria "c2" {
+				monitor "col2" {
+					level 1 when value > 1;
+					level 2 when value > 3;
+				}
+			}
+			`
+
+	inputDataset := `customer_id,col1,col2
+1001,3,0
+1002,6,2
+1003,11,5
+`
+
+	ast, errorParse := dslalert.Parse(strings.NewReader(inputCriteria))
+	require.Empty(t,
+		errorParse,
+		"should have no parsing errors",
+	)
+
+	// the dataset is read once, the criteria evaluated in parallel.
+	results, errEvaluate := dslalert.EvaluateConfiguration(
+		context.Background(),
+		ast,
+		strings.NewReader(inputDataset),
+	)
+	require.NoError(t, errEvaluate)
+	require.Len(t, results, 2)
+
+	for _, criteria := range results {
+		require.NotEmpty(t, criteria.Results)
+
+		fmt.Printf(
+			"\n%s\n%s",
+			criteria.Results.Message(),
+			criteria.Results,
+		)
+	}
+}
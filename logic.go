@@ -2,16 +2,49 @@ package dslalert
 
 import (
 	"bytes"
-	"fmt"
+	"errors"
 	"io"
+	"slices"
 )
 
-func Parse(input io.Reader) (*AlertConfiguration, []string) {
+// ParamsParse configures ParseWithParams.
+type ParamsParse struct {
+	// Functions exposes host functions to the rules of this configuration.
+	Functions *FunctionRegistry
+
+	// Columns are the columns of the datasets, see ParamsValidate.
+	Columns []string
+
+	// IsTolerant returns the partial configuration even with errors,
+	// broken criteria, monitors and rules being marked IsError.
+	IsTolerant bool
+
+	// IsSyntaxOnly reports syntax errors only, skipping the validation
+	// and keeping calls of unknown functions unresolved, e.g. to format
+	// a configuration without its host functions. Such a configuration
+	// is fit for Format, not for evaluation.
+	IsSyntaxOnly bool
+}
+
+// Parse reads and validates an alert configuration. On any error the
+// configuration is nil and the diagnostics describe every problem found.
+// See ParamsParse.IsTolerant to keep the well-formed parts.
+func Parse(input io.Reader) (*AlertConfiguration, Diagnostics) {
+	return ParseWithParams(input, &ParamsParse{})
+}
+
+func ParseWithParams(input io.Reader, params *ParamsParse) (*AlertConfiguration, Diagnostics) {
 	buf := make([]byte, 1)
 	n, err := input.Read(buf)
 	if n == 0 || err == io.EOF {
 		return nil,
-			[]string{"input is empty"}
+			Diagnostics{
+				{
+					Severity: SeverityError,
+					Code:     CodeEmptyInput,
+					Message:  "input is empty",
+				},
+			}
 	}
 
 	l := newLexer(
@@ -20,29 +53,46 @@ func Parse(input io.Reader) (*AlertConfiguration, []string) {
 
 	p := newParser(
 		&paramsNewParser{
-			Lexer: l,
+			Lexer:        l,
+			Functions:    params.Functions,
+			IsSyntaxOnly: params.IsSyntaxOnly,
 		},
 	)
 
-	programAST, errParser := p.parserEntrypoint()
+	programAST := p.parserEntrypoint()
 
-	if l.errorParsing != nil {
-		p.errors = append(
-			p.errors,
-			fmt.Sprintf("lexer error: %v", l.errorParsing),
-		)
+	p.diagnostics = append(l.diagnostics, p.diagnostics...)
+
+	var errLexer Diagnostic
+	if errors.As(l.errorParsing, &errLexer) {
+		p.diagnostics = append(p.diagnostics, errLexer)
 	}
 
-	if len(p.errors) > 0 {
-		return nil,
-			p.errors
+	slices.SortStableFunc(
+		p.diagnostics,
+		func(a, b Diagnostic) int {
+			return a.Start.Offset - b.Start.Offset
+		},
+	)
+
+	if !params.IsSyntaxOnly {
+		p.diagnostics = append(
+			p.diagnostics,
+
+			ValidateWithParams(
+				programAST,
+				&ParamsValidate{
+					Columns: params.Columns,
+				},
+			)...,
+		)
 	}
 
-	if errParser != nil {
+	if p.diagnostics.HasErrors() && !params.IsTolerant {
 		return nil,
-			p.errors
+			p.diagnostics
 	}
 
 	return programAST,
-		nil
+		p.diagnostics
 }
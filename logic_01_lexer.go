@@ -4,12 +4,25 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"text/scanner"
 )
 
 type dslLexer struct {
 	scaner       scanner.Scanner
-	errorParsing error
+	errorParsing error       // invalid string literal, ending the input
+	diagnostics  Diagnostics // unexpected characters, skipped
+
+	lineLastToken    int       // line where the previous token ended
+	commentsLeading  []comment // candidate doc comment for the next token
+	commentsDetached []string  // comment groups before the next token, not its doc
+	commentTrailing  string    // comment on the line of the previous token
+}
+
+type comment struct {
+	text      string
+	lineStart int
+	lineEnd   int
 }
 
 func newLexer(reader io.Reader) *dslLexer {
@@ -19,20 +32,105 @@ func newLexer(reader io.Reader) *dslLexer {
 	s.Mode = scanner.ScanIdents |
 		scanner.ScanFloats |
 		scanner.ScanStrings |
+		scanner.ScanRawStrings | // `raw` strings spare escaping in patterns
 		scanner.ScanChars |
 		scanner.ScanComments
 
 	// customize scanner if needed, e.g., operators
-	s.IsIdentRune = func(ch rune, i int) bool {
-		return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch == '_') || (i > 0 && ch >= '0' && ch <= '9')
-	}
+	s.IsIdentRune = isIdentifierRune
 
 	return &dslLexer{
 		scaner: s,
 	}
 }
 
+// nextToken returns the next token with the comments preceding it:
+// the doc comment group ending on the line above, the groups separated
+// from it by blank lines, and the comment that trailed the previous token
+// on its line.
 func (l *dslLexer) nextToken() token {
+	result := l.scanToken()
+
+	result.end = l.scaner.Pos()
+	result.doc, result.commentsDetached, result.commentTrailing = l.takeComments(result.pos.Line)
+	l.lineLastToken = result.end.Line
+
+	return result
+}
+
+func (l *dslLexer) collectComment(raw string, position scanner.Position) {
+	current := comment{
+		text:      commentText(raw),
+		lineStart: position.Line,
+		lineEnd:   position.Line + strings.Count(raw, "\n"),
+	}
+
+	if current.lineStart == l.lineLastToken && len(l.commentsLeading) == 0 {
+		l.commentTrailing = strings.TrimSpace(l.commentTrailing + "\n" + current.text)
+
+		return
+	}
+
+	// a blank line separates comment groups, only the last one is a doc.
+	if count := len(l.commentsLeading); count > 0 &&
+		current.lineStart > l.commentsLeading[count-1].lineEnd+1 {
+		l.commentsDetached = append(l.commentsDetached, l.groupLeading())
+	}
+
+	l.commentsLeading = append(l.commentsLeading, current)
+}
+
+func (l *dslLexer) takeComments(line int) (string, []string, string) {
+	var doc string
+
+	if count := len(l.commentsLeading); count > 0 {
+		if l.commentsLeading[count-1].lineEnd >= line-1 {
+			doc = l.groupLeading()
+		} else {
+			l.commentsDetached = append(l.commentsDetached, l.groupLeading())
+		}
+	}
+
+	detached, trailing := l.commentsDetached, l.commentTrailing
+
+	l.commentsDetached = nil
+	l.commentTrailing = ""
+
+	return doc, detached, trailing
+}
+
+// groupLeading joins the leading comments into one group, taking them.
+func (l *dslLexer) groupLeading() string {
+	texts := make([]string, len(l.commentsLeading))
+
+	for ix, leading := range l.commentsLeading {
+		texts[ix] = leading.text
+	}
+
+	l.commentsLeading = nil
+
+	return strings.Join(texts, "\n")
+}
+
+// commentText strips the comment markers and surrounding blanks.
+func commentText(raw string) string {
+	if strings.HasPrefix(raw, "//") {
+		return strings.TrimSpace(raw[2:])
+	}
+
+	lines := strings.Split(
+		strings.TrimSuffix(strings.TrimPrefix(raw, "/*"), "*/"),
+		"\n",
+	)
+
+	for ix, line := range lines {
+		lines[ix] = strings.TrimSpace(line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func (l *dslLexer) scanToken() token {
 	if l.errorParsing != nil {
 		return token{
 			kind:         tokenError,
@@ -41,6 +139,13 @@ func (l *dslLexer) nextToken() token {
 	}
 
 	currentToken := l.scaner.Scan()
+
+	for currentToken == scanner.Comment {
+		l.collectComment(l.scaner.TokenText(), l.scaner.Position)
+
+		currentToken = l.scaner.Scan()
+	}
+
 	literalToken := l.scaner.TokenText()
 	position := l.scaner.Position
 
@@ -81,6 +186,73 @@ func (l *dslLexer) nextToken() token {
 				pos:          position,
 			}
 
+		case _dslWithin:
+			return token{
+				kind:         tokenWithin,
+				valueLiteral: literalToken,
+				pos:          position,
+			}
+
+		case _dslIf:
+			return token{
+				kind:         tokenIf,
+				valueLiteral: literalToken,
+				pos:          position,
+			}
+
+		case _dslThen:
+			return token{
+				kind:         tokenThen,
+				valueLiteral: literalToken,
+				pos:          position,
+			}
+
+		case _dslElse:
+			return token{
+				kind:         tokenElse,
+				valueLiteral: literalToken,
+				pos:          position,
+			}
+
+		case _dslCase:
+			return token{
+				kind:         tokenCase,
+				valueLiteral: literalToken,
+				pos:          position,
+			}
+
+		case _dslEnd:
+			return token{
+				kind:         tokenEnd,
+				valueLiteral: literalToken,
+				pos:          position,
+			}
+
+		case _dslTrue, _dslFalse:
+			return token{
+				kind:         tokenBoolean,
+				valueLiteral: literalToken,
+				pos:          position,
+			}
+
+		case _dslNull:
+			return token{
+				kind:         tokenNull,
+				valueLiteral: literalToken,
+				pos:          position,
+			}
+
+		case _dslAnd, _dslOr, _dslNot,
+			_dslContains, _dslStartsWith, _dslEndsWith, _dslMatches,
+			_dslIn, _dslBetween:
+			// logical connectives, string matchers and membership tests
+			// are keywords but behave as operators.
+			return token{
+				kind:         tokenOperator,
+				valueLiteral: literalToken,
+				pos:          position,
+			}
+
 		default:
 			return token{
 				kind:         tokenIdentifier,
@@ -89,14 +261,20 @@ func (l *dslLexer) nextToken() token {
 			}
 		}
 
-	case scanner.String:
+	case scanner.String, scanner.RawString:
 		unquoted, err := strconv.Unquote(literalToken)
 		if err != nil {
-			l.errorParsing = fmt.Errorf(
-				"invalid string literal at %s: %w",
-				position,
-				err,
-			)
+			l.errorParsing = Diagnostic{
+				Severity: SeverityError,
+				Code:     CodeInvalidString,
+				Start:    position,
+				End:      l.scaner.Pos(),
+				Message: fmt.Sprintf(
+					"invalid string literal %s: %v",
+					literalToken,
+					err,
+				),
+			}
 
 			return token{
 				kind:         tokenError,
@@ -132,7 +310,52 @@ func (l *dslLexer) nextToken() token {
 			pos:          position,
 		}
 
+	case '(':
+		return token{
+			kind:         tokenLeftParen,
+			valueLiteral: literalToken,
+			pos:          position,
+		}
+
+	case ')':
+		return token{
+			kind:         tokenRightParen,
+			valueLiteral: literalToken,
+			pos:          position,
+		}
+
+	case '[':
+		return token{
+			kind:         tokenLeftBracket,
+			valueLiteral: literalToken,
+			pos:          position,
+		}
+
+	case ']':
+		return token{
+			kind:         tokenRightBracket,
+			valueLiteral: literalToken,
+			pos:          position,
+		}
+
+	case ',':
+		return token{
+			kind:         tokenComma,
+			valueLiteral: literalToken,
+			pos:          position,
+		}
+
 	case '=':
+		if l.scaner.Peek() == '=' {
+			l.scaner.Scan() // consume second '='
+
+			return token{
+				kind:         tokenOperator,
+				valueLiteral: "==",
+				pos:          position,
+			}
+		}
+
 		return token{
 			kind:         tokenAssign,
 			valueLiteral: literalToken,
@@ -146,8 +369,8 @@ func (l *dslLexer) nextToken() token {
 			pos:          position,
 		}
 
-	case '>', '<', '+', '-', '*', '/':
-		// peek ahead for multi-char operators like >=, <=, ==, !=
+	case '>', '<', '+', '-', '*', '/', '!':
+		// peek ahead for multi-char operators like >=, <=, !=
 		next := l.scaner.Peek()
 		if literalToken == ">" && next == '=' {
 			l.scaner.Scan() // consume '='
@@ -169,16 +392,6 @@ func (l *dslLexer) nextToken() token {
 			}
 		}
 
-		if literalToken == "=" && next == '=' {
-			l.scaner.Scan() // consume '='
-
-			return token{
-				kind:         tokenOperator,
-				valueLiteral: "==",
-				pos:          position,
-			}
-		}
-
 		if literalToken == "!" && next == '=' {
 			l.scaner.Scan() // consume '='
 
@@ -196,17 +409,21 @@ func (l *dslLexer) nextToken() token {
 		}
 
 	default:
-		// handle other chars or report error
-		l.errorParsing = fmt.Errorf(
-			"unexpected character '%s' at %s",
-			literalToken,
-			position,
+		// reported once and skipped, the tokens after it still parse.
+		l.diagnostics = append(
+			l.diagnostics,
+			Diagnostic{
+				Severity: SeverityError,
+				Code:     CodeInvalidCharacter,
+				Start:    position,
+				End:      l.scaner.Pos(),
+				Message: fmt.Sprintf(
+					"unexpected character '%s'",
+					literalToken,
+				),
+			},
 		)
 
-		return token{
-			kind:         tokenError,
-			valueLiteral: l.errorParsing.Error(),
-			pos:          position,
-		}
+		return l.scanToken()
 	}
 }
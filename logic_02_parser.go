@@ -1,9 +1,9 @@
 package dslalert
 
 import (
-	"errors"
 	"fmt"
 	"slices"
+	"text/scanner"
 )
 
 type parser struct {
@@ -11,21 +11,29 @@ type parser struct {
 
 	tokenCurrent token
 	tokenNext    token
+	endPrevious  scanner.Position // end of the last consumed token
 
-	errors []string
+	functions    *FunctionRegistry
+	isSyntaxOnly bool // unknown functions are kept unresolved
+
+	diagnostics Diagnostics
 
 	debug bool
 }
 
 type paramsNewParser struct {
-	Lexer       *dslLexer
-	IsDebugMode bool
+	Lexer        *dslLexer
+	Functions    *FunctionRegistry
+	IsSyntaxOnly bool
+	IsDebugMode  bool
 }
 
 func newParser(params *paramsNewParser) *parser {
 	p := parser{
-		lex:   params.Lexer,
-		debug: params.IsDebugMode,
+		lex:          params.Lexer,
+		functions:    params.Functions,
+		isSyntaxOnly: params.IsSyntaxOnly,
+		debug:        params.IsDebugMode,
 	}
 
 	p.tokenNext = p.lex.nextToken()
@@ -41,6 +49,7 @@ func newParser(params *paramsNewParser) *parser {
 }
 
 func (p *parser) advanceToken() {
+	p.endPrevious = p.tokenCurrent.end
 	p.tokenCurrent = p.tokenNext
 	p.tokenNext = p.lex.nextToken()
 }
@@ -52,45 +61,83 @@ func (p *parser) logTokenState() {
 	}
 }
 
-func (p *parser) errorf(format string, args ...any) {
-	p.errors = append(
-		p.errors,
+// errorf reports an error spanning the current token.
+func (p *parser) errorf(code Code, format string, args ...any) {
+	p.report(
+		Diagnostic{
+			Code:    code,
+			Message: fmt.Sprintf(format, args...),
+		},
+	)
+}
 
-		fmt.Sprintf(
-			"parse error at %s: %s",
-			p.tokenCurrent.pos,
-			fmt.Sprintf(format, args...),
-		),
+// errorAt reports an error spanning start..end, e.g. a whole sub-expression.
+func (p *parser) errorAt(code Code, start, end scanner.Position, format string, args ...any) {
+	p.report(
+		Diagnostic{
+			Code:    code,
+			Start:   start,
+			End:     end,
+			Message: fmt.Sprintf(format, args...),
+		},
 	)
 }
 
+// report records a diagnostic, defaulting to an error on the current token.
+// Nothing is recorded on a lexer error token, the lexer already reported it.
+func (p *parser) report(diagnostic Diagnostic) {
+	if p.currentTokenIs(tokenError) {
+		return
+	}
+
+	if diagnostic.Severity == 0 {
+		diagnostic.Severity = SeverityError
+	}
+
+	if !diagnostic.Start.IsValid() {
+		diagnostic.Start = p.tokenCurrent.pos
+		diagnostic.End = p.tokenCurrent.end
+	}
+
+	if diagnostic.Code == CodeUnexpectedToken &&
+		diagnostic.Hint == "" &&
+		p.currentTokenIs(tokenIdentifier) {
+		if keyword := suggestKeyword(p.tokenCurrent.valueLiteral); keyword != "" {
+			diagnostic.Hint = fmt.Sprintf("did you mean `%s`?", keyword)
+		}
+	}
+
+	p.diagnostics = append(p.diagnostics, diagnostic)
+}
+
+// tryRecoverAtBlockEnd skips the rest of a broken block through its
+// closing brace, whether or not its opening brace was already read,
+// so parsing resumes after the block.
 func (p *parser) tryRecoverAtBlockEnd() {
-	if !p.currentTokenIs(tokenRightBrace) && !p.currentTokenIs(tokenEOF) {
+	p.skipTo(tokenLeftBrace, tokenRightBrace)
+
+	if p.currentTokenIs(tokenLeftBrace) {
+		p.advanceToken()
+		p.skipToRightBrace()
+	}
+
+	if p.currentTokenIs(tokenRightBrace) {
 		p.advanceToken()
 	}
 }
 
 type paramsExpect struct {
-	Caller       string
 	KindExpected tokenKind
 }
 
 func (p *parser) expectWTokenAdvance(params *paramsExpect) bool {
-	if p.tokenCurrent.kind == params.KindExpected {
-		p.advanceToken()
-
-		return true
+	if !p.expectNoTokenAdvance(params) {
+		return false
 	}
 
-	p.errorf(
-		"Caller:%s\nExpected token %v, got %v (%s)",
-		params.Caller,
-		params.KindExpected,
-		p.tokenCurrent.kind,
-		p.tokenCurrent.valueLiteral,
-	)
+	p.advanceToken()
 
-	return false
+	return true
 }
 
 func (p *parser) expectNoTokenAdvance(params *paramsExpect) bool {
@@ -98,86 +145,93 @@ func (p *parser) expectNoTokenAdvance(params *paramsExpect) bool {
 		return true
 	}
 
-	p.errorf(
-		"Caller:%s\nExpected token %v, got %v (%s)",
-		params.Caller,
-		params.KindExpected,
-		p.tokenCurrent.kind,
-		p.tokenCurrent.valueLiteral,
+	p.report(
+		Diagnostic{
+			Code: CodeUnexpectedToken,
+			Message: fmt.Sprintf(
+				"expected %s, got %s",
+				params.KindExpected,
+				p.tokenCurrent.describe(),
+			),
+			Expected: []string{
+				params.KindExpected.String(),
+			},
+		},
 	)
 
 	return false
 }
 
-func (p *parser) parserEntrypoint() (*AlertConfiguration, error) {
-	var result AlertConfiguration
+// parserEntrypoint reads every criteria of the input. Broken criteria are
+// kept as error nodes, the diagnostics tell whether any was found.
+func (p *parser) parserEntrypoint() *AlertConfiguration {
+	result := AlertConfiguration{
+		Span: Span{
+			Start: p.tokenCurrent.pos,
+		},
+	}
+
+	for !p.isAtEnd() {
+		if p.currentTokenIs(tokenCriteria) {
+			result.Criterias = append(result.Criterias, p.parseCriteria())
 
-	for {
-		if p.tokenCurrent.kind == tokenEOF || p.tokenCurrent.kind == tokenError {
-			break
+			continue
 		}
 
-		if p.tokenCurrent.kind == tokenCriteria {
-			criteria := p.parseCriteria()
-			if criteria != nil {
-				result.Criterias = append(result.Criterias, criteria)
+		p.errorf(
+			CodeUnexpectedToken,
+			"unexpected %s at program root",
+			p.tokenCurrent.describe(),
+		)
 
-				continue // Successfully parsed, move to next token
-			}
+		p.advanceToken()
+		p.skipTo(tokenCriteria)
+	}
 
-			return nil,
-				errors.New(
-					"there is an error",
-				)
-		}
+	result.End = p.endPrevious
+	result.Dangling = p.tokenCurrent.commentGroups()
+
+	return &result
+}
 
-		// Unexpected token - attempt recovery
-		if p.tokenCurrent.kind != tokenEOF {
-			p.errorf(
-				"unexpected token at program root: %v (%s)",
-				p.tokenCurrent.kind,
-				p.tokenCurrent.valueLiteral,
-			)
+// isAtEnd reports whether no more tokens can be read,
+// at the end of input or after a lexer error.
+func (p *parser) isAtEnd() bool {
+	return p.currentTokenIs(tokenEOF) || p.currentTokenIs(tokenError)
+}
 
-			p.skipToIdentifier(_dslCriteria)
+// skipTo advances to the next token of one of the kinds, the recovery point
+// where parsing resumes after an error. Nested blocks are skipped whole.
+func (p *parser) skipTo(kinds ...tokenKind) {
+	var depth int
 
-			continue
+	for !p.isAtEnd() {
+		if depth == 0 && slices.Contains(kinds, p.tokenCurrent.kind) {
+			return
 		}
 
-		// Safety net - advance if we're stuck
-		p.advanceToken()
-	}
-
-	return &result,
-		nil
-}
+		switch p.tokenCurrent.kind {
+		case tokenLeftBrace:
+			depth++
 
-func (p *parser) skipToIdentifier(identifiers ...string) {
-	for p.tokenCurrent.kind != tokenEOF && p.tokenCurrent.kind != tokenError {
-		if p.tokenCurrent.kind == tokenIdentifier &&
-			slices.Contains(
-				identifiers,
-				p.tokenCurrent.valueLiteral,
-			) {
-			return // found one of the target identifiers
+		case tokenRightBrace:
+			depth--
 		}
 
 		p.advanceToken()
 	}
 }
 
-func (p *parser) skipToIdentifierRightBrace(identifiers ...string) {
-	for p.tokenCurrent.kind != tokenEOF && p.tokenCurrent.kind != tokenError {
-		if p.tokenCurrent.kind == tokenRightBrace {
-			return // Stop at closing brace
-		}
-
-		if p.tokenCurrent.kind == tokenIdentifier &&
-			slices.Contains(identifiers, p.tokenCurrent.valueLiteral) {
-			return // Found target identifier
-		}
+// skipToRightBrace is skipTo not leaving the current block.
+func (p *parser) skipToRightBrace(kinds ...tokenKind) {
+	p.skipTo(append(kinds, tokenRightBrace)...)
+}
 
-		p.advanceToken()
+// spanFrom is the span from start up to the end of the last consumed token.
+func (p *parser) spanFrom(start scanner.Position) Span {
+	return Span{
+		Start: start,
+		End:   p.endPrevious,
 	}
 }
 
@@ -1,26 +1,32 @@
 package dslalert
 
-func (p *parser) parseCriteria() *criteria {
-	var result criteria
+// parseCriteria reads a criteria block. A broken criteria is returned as
+// an error node holding the monitors that could be read.
+func (p *parser) parseCriteria() *Criteria {
+	result := Criteria{
+		Span: Span{
+			Start: p.tokenCurrent.pos,
+		},
+		Detached: p.tokenCurrent.commentsDetached,
+		Doc:      p.tokenCurrent.doc,
+	}
 
 	// 1. Criteria keyword
 	if !p.expectWTokenAdvance(
 		&paramsExpect{
-			Caller:       "parseCriteria - 1",
 			KindExpected: tokenCriteria,
 		},
 	) {
-		return nil
+		return p.criteriaError(&result)
 	}
 
 	// 2. Criteria name (string)
 	if !p.expectNoTokenAdvance(
 		&paramsExpect{
-			Caller:       "parseCriteria - 2",
 			KindExpected: tokenStringLiteral,
 		},
 	) {
-		return nil
+		return p.criteriaError(&result)
 	}
 
 	result.Name = p.tokenCurrent.valueLiteral
@@ -29,67 +35,79 @@ func (p *parser) parseCriteria() *criteria {
 	// 3. Opening brace
 	if !p.expectWTokenAdvance(
 		&paramsExpect{
-			Caller:       "parseCriteria - 3",
 			KindExpected: tokenLeftBrace,
 		},
 	) {
-		return nil
+		return p.criteriaError(&result)
 	}
 
+	result.CommentOpening = p.tokenCurrent.commentTrailing
+
 	// 4. Check for empty block
 	if p.currentTokenIs(tokenRightBrace) {
 		p.errorf(
-			"Caller:%s\nEmpty criteria block not allowed",
-			"parseCriteria - 4",
+			CodeEmptyBlock,
+			"empty criteria block not allowed",
 		)
 
-		p.advanceToken() // Consume the '}' to avoid hanging
-
-		return nil
+		return p.criteriaError(&result)
 	}
 
 	// 5. Body parsing (improved keyword detection)
-	for !p.currentTokenIs(tokenRightBrace) {
+	for !p.currentTokenIs(tokenRightBrace) && !p.isAtEnd() {
 		switch p.tokenCurrent.kind { // Switch on kind, not valueLiteral
 		case tokenIdentifier:
-			switch p.tokenCurrent.valueLiteral {
-			default:
-				p.errorf(
-					"Caller:%s\nUnexpected identifier: %s",
-					"parseCriteria - 4",
-					p.tokenCurrent.valueLiteral,
-				)
-			}
+			p.errorf(
+				CodeUnexpectedToken,
+				"unexpected identifier: %s",
+				p.tokenCurrent.valueLiteral,
+			)
 
 		case tokenMonitor:
-			if monitor := p.parseMonitor(); monitor != nil {
-				result.Monitors = append(result.Monitors, monitor)
+			result.Monitors = append(result.Monitors, p.parseMonitor())
 
-				continue
-			}
+			continue
 
 		default:
 			p.errorf(
-				"Caller:%s\nUnexpected token: %v",
-				"parseCriteria - 5",
-				p.tokenCurrent.kind,
+				CodeUnexpectedToken,
+				"unexpected %s",
+				p.tokenCurrent.describe(),
 			)
 		}
 
-		p.skipToIdentifierRightBrace("baseline", "increment", _dslMonitor)
+		result.IsError = true
+
+		p.advanceToken()
+		p.skipToRightBrace(tokenMonitor)
 	}
 
-	// 5. Closing brace
+	// 6. Closing brace
+	result.Dangling = p.tokenCurrent.commentGroups()
+
 	if !p.expectWTokenAdvance(
 		&paramsExpect{
-			Caller:       "parseCriteria - 6",
 			KindExpected: tokenRightBrace,
 		},
 	) {
-		p.tryRecoverAtBlockEnd()
+		result.IsError = true
+		result.End = p.endPrevious
 
-		return nil
+		return &result
 	}
 
+	result.End = p.endPrevious
+	result.Comment = p.tokenCurrent.commentTrailing
+
 	return &result
 }
+
+func (p *parser) criteriaError(result *Criteria) *Criteria {
+	result.IsError = true
+
+	p.tryRecoverAtBlockEnd()
+
+	result.End = p.endPrevious
+
+	return result
+}
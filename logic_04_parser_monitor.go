@@ -1,26 +1,32 @@
 package dslalert
 
-func (p *parser) parseMonitor() *monitor {
-	var result monitor
+// parseMonitor reads a monitor block. A broken monitor is returned as
+// an error node holding the rules that could be read.
+func (p *parser) parseMonitor() *Monitor {
+	result := Monitor{
+		Span: Span{
+			Start: p.tokenCurrent.pos,
+		},
+		Detached: p.tokenCurrent.commentsDetached,
+		Doc:      p.tokenCurrent.doc,
+	}
 
 	// 1. Monitor keyword
 	if !p.expectWTokenAdvance(
 		&paramsExpect{
-			Caller:       "parseMonitor - 1",
 			KindExpected: tokenMonitor,
 		},
 	) {
-		return nil
+		return p.monitorError(&result)
 	}
 
 	// 2. Monitor name (string)
 	if !p.expectNoTokenAdvance(
 		&paramsExpect{
-			Caller:       "parseMonitor - 2",
 			KindExpected: tokenStringLiteral,
 		},
 	) {
-		return nil
+		return p.monitorError(&result)
 	}
 
 	result.ColumnName = p.tokenCurrent.valueLiteral
@@ -29,58 +35,75 @@ func (p *parser) parseMonitor() *monitor {
 	// 3. Opening brace
 	if !p.expectWTokenAdvance(
 		&paramsExpect{
-			Caller:       "parseMonitor - 3",
 			KindExpected: tokenLeftBrace,
 		},
 	) {
-		return nil
+		return p.monitorError(&result)
 	}
 
+	result.CommentOpening = p.tokenCurrent.commentTrailing
+
 	if p.currentTokenIs(tokenRightBrace) {
-		p.errorf("monitor must contain at least one level rule")
+		p.errorf(
+			CodeEmptyBlock,
+			"monitor must contain at least one level rule",
+		)
 
-		return nil
+		return p.monitorError(&result)
 	}
 
-	// 4. Body parsing with strict advancement control
-	for !p.currentTokenIs(tokenRightBrace) && !p.currentTokenIs(tokenEOF) {
-		switch {
-		case p.currentTokenIs(tokenLevel):
-			if r := p.parseRule(); r != nil {
-				result.Rules = append(result.Rules, r)
-
-				continue
-			}
-			// Fallthrough to error handling if parseRule failed
-
-		default:
-			p.errorf(
-				"parseMonitor - 4: unexpected token %v (%s)",
-				p.tokenCurrent.kind,
-				p.tokenCurrent.valueLiteral,
-			)
-		}
+	// 4. Body parsing, a broken rule resumes at the next rule
+	for !p.currentTokenIs(tokenRightBrace) && !p.isAtEnd() {
+		if p.currentTokenIs(tokenLevel) {
+			result.Rules = append(result.Rules, p.parseRule())
 
-		// Critical: ensure token advancement in all cases
-		if !p.currentTokenIs(tokenRightBrace) && !p.currentTokenIs(tokenEOF) {
-			p.advanceToken()
+			continue
 		}
+
+		p.errorf(
+			CodeUnexpectedToken,
+			"unexpected %s",
+			p.tokenCurrent.describe(),
+		)
+
+		result.IsError = true
+
+		p.advanceToken()
+		p.skipToRightBrace(tokenLevel)
 	}
 
 	// 5. Closing brace validation
+	result.Dangling = p.tokenCurrent.commentGroups()
+
 	if !p.expectWTokenAdvance(
 		&paramsExpect{
-			Caller:       "parseMonitor - 5",
 			KindExpected: tokenRightBrace,
 		},
 	) {
 		p.errorf(
+			CodeUnexpectedToken,
 			"missing closing brace '}' for monitor '%s'",
 			result.ColumnName,
 		)
 
-		return nil
+		result.IsError = true
+		result.End = p.endPrevious
+
+		return &result
 	}
 
+	result.End = p.endPrevious
+	result.Comment = p.tokenCurrent.commentTrailing
+
 	return &result
 }
+
+func (p *parser) monitorError(result *Monitor) *Monitor {
+	result.IsError = true
+
+	p.tryRecoverAtBlockEnd()
+
+	result.End = p.endPrevious
+
+	return result
+}
@@ -2,38 +2,53 @@ package dslalert
 
 import "strconv"
 
-func (p *parser) parseRule() *rule {
-	var result rule
+// parseRule reads a level rule. A broken rule is returned as an error node
+// once its tokens are skipped through the closing ';'.
+func (p *parser) parseRule() *Rule {
+	result := Rule{
+		Span: Span{
+			Start: p.tokenCurrent.pos,
+		},
+		Detached: p.tokenCurrent.commentsDetached,
+		Doc:      p.tokenCurrent.doc,
+	}
 
 	// 1. Level keyword
 	if !p.expectWTokenAdvance(
 		&paramsExpect{
-			Caller:       "parseRule - 1",
 			KindExpected: tokenLevel,
 		},
 	) {
-		return nil
+		return p.ruleError(&result)
+	}
+
+	// 2. Number, a sign read along for Validate to report negative levels
+	var sign string
+
+	if p.currentTokenIs(tokenOperator) && p.tokenCurrent.valueLiteral == "-" {
+		sign = "-"
+
+		p.advanceToken()
 	}
 
-	// 2. Number
 	if !p.expectNoTokenAdvance(
 		&paramsExpect{
-			Caller:       "parseRule - 2",
 			KindExpected: tokenNumber,
 		},
 	) {
-		return nil
+		return p.ruleError(&result)
 	}
 
-	level, err := strconv.Atoi(p.tokenCurrent.valueLiteral)
+	level, err := strconv.Atoi(sign + p.tokenCurrent.valueLiteral)
 	if err != nil {
 		p.errorf(
+			CodeInvalidNumber,
 			"invalid level number '%s': %v",
-			p.tokenCurrent.valueLiteral,
+			sign+p.tokenCurrent.valueLiteral,
 			err,
 		)
 
-		return nil
+		return p.ruleError(&result)
 	}
 
 	result.Level = level
@@ -43,30 +58,50 @@ func (p *parser) parseRule() *rule {
 	// 3. When
 	if !p.expectWTokenAdvance(
 		&paramsExpect{
-			Caller:       "parseRule - 3",
 			KindExpected: tokenWhen,
 		},
 	) {
-		return nil
+		return p.ruleError(&result)
 	}
 
 	p.logTokenState()
 
 	result.Condition = p.parseExpression(0) // parse the condition expression
 	if result.Condition == nil {
-		p.errorf("invalid rule condition expression")
+		p.errorf(
+			CodeInvalidCondition,
+			"invalid rule condition expression",
+		)
 
-		return nil
+		return p.ruleError(&result)
 	}
 
 	if !p.expectWTokenAdvance(
 		&paramsExpect{
-			Caller:       "parseRule - 4",
 			KindExpected: tokenSemicolon,
 		},
 	) {
-		return nil
+		return p.ruleError(&result)
 	}
 
+	result.End = p.endPrevious
+	result.Comment = p.tokenCurrent.commentTrailing
+
 	return &result
 }
+
+// ruleError skips to the end of the broken rule, stopping before the next
+// rule or the end of the monitor when its ';' is missing.
+func (p *parser) ruleError(result *Rule) *Rule {
+	result.IsError = true
+
+	p.skipToRightBrace(tokenSemicolon, tokenLevel)
+
+	if p.currentTokenIs(tokenSemicolon) {
+		p.advanceToken()
+	}
+
+	result.End = p.endPrevious
+
+	return result
+}
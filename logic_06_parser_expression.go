@@ -1,69 +1,206 @@
 package dslalert
 
-import "strconv"
+import (
+	"regexp"
+	"strconv"
+	"text/scanner"
+)
 
 func (p *parser) currentPrecedence() int {
 	if p.tokenCurrent.kind != tokenOperator {
 		return 0
 	}
 
-	return p.operatorPrecedence(p.tokenCurrent.valueLiteral)
+	if p.isNegatedMembership() {
+		return operatorPrecedence(p.tokenNext.valueLiteral)
+	}
+
+	return operatorPrecedence(p.tokenCurrent.valueLiteral)
+}
+
+// isNegatedMembership reports an infix 'not in' or 'not between'.
+func (p *parser) isNegatedMembership() bool {
+	return p.tokenCurrent.valueLiteral == _dslNot &&
+		p.tokenNext.kind == tokenOperator &&
+		(p.tokenNext.valueLiteral == _dslIn || p.tokenNext.valueLiteral == _dslBetween)
 }
 
-func (p *parser) operatorPrecedence(op string) int {
+// precedenceNot is the binding power of the 'not' prefix operator:
+// it binds looser than comparisons but tighter than 'and' / 'or'.
+const precedenceNot = 2
+
+// precedencePrefix is the binding power of the symbolic prefix operators
+// '-', '+' and '!': they bind tighter than any binary operator.
+const precedencePrefix = 6
+
+func operatorPrecedence(op string) int {
 	switch op {
 	case "*", "/":
 		return 5
 	case "+", "-":
 		return 4
-	case ">", "<", ">=", "<=", "==", "!=":
+	case ">", "<", ">=", "<=", "==", "!=",
+		_dslContains, _dslStartsWith, _dslEndsWith, _dslMatches,
+		_dslIn, _dslNotIn, _dslBetween:
 		return 3
+	case _dslAnd:
+		return 2
+	case _dslOr:
+		return 1
 
 	default:
 		return 0
 	}
 }
 
-func (p *parser) parseExpression(precedence int) expression {
-	var left expression
+func (p *parser) parseExpression(precedence int) Expression {
+	var left Expression
+
+	start := p.tokenCurrent.pos
 
 	switch p.tokenCurrent.kind {
 	case tokenNumber:
 		valueFloat, errFloat := strconv.ParseFloat(p.tokenCurrent.valueLiteral, 64)
-		if errFloat == nil {
-			left = newliteral(
-				valueFloat,
+		if errFloat != nil {
+			p.errorf(
+				CodeInvalidNumber,
+				"invalid number literal: %s",
 				p.tokenCurrent.valueLiteral,
 			)
-		} else {
-			valueInteger, errInteger := strconv.Atoi(p.tokenCurrent.valueLiteral)
-			if errInteger == nil {
-				left = newliteral(
-					valueInteger,
-					p.tokenCurrent.valueLiteral,
-				)
-			} else {
-				p.errorf(
-					"invalid number literal: %s",
-					p.tokenCurrent.valueLiteral,
-				)
 
+			return nil
+		}
+
+		literal := newLiteral(
+			numberValue(valueFloat),
+			p.tokenCurrent.valueLiteral,
+		)
+
+		p.advanceToken()
+
+		literal.Span = p.spanFrom(start)
+		left = literal
+
+	case tokenBoolean:
+		literal := newLiteral(
+			boolValue(p.tokenCurrent.valueLiteral == _dslTrue),
+			p.tokenCurrent.valueLiteral,
+		)
+
+		p.advanceToken()
+
+		literal.Span = p.spanFrom(start)
+		left = literal
+
+	case tokenNull:
+		literal := newLiteral(
+			nullValue(),
+			p.tokenCurrent.valueLiteral,
+		)
+
+		p.advanceToken()
+
+		literal.Span = p.spanFrom(start)
+		left = literal
+
+	case tokenStringLiteral:
+		literal := newLiteral(
+			stringValue(p.tokenCurrent.valueLiteral),
+			strconv.Quote(p.tokenCurrent.valueLiteral),
+		)
+
+		p.advanceToken()
+
+		literal.Span = p.spanFrom(start)
+		left = literal
+
+	case tokenIdentifier:
+		if p.tokenNext.kind == tokenLeftParen {
+			left = p.parseCall()
+			if left == nil {
 				return nil
 			}
+
+			break
 		}
 
+		variable := newVariable(p.tokenCurrent.valueLiteral)
+
 		p.advanceToken()
 
-	case tokenIdentifier:
-		left = newVariable(p.tokenCurrent.valueLiteral)
+		variable.Span = p.spanFrom(start)
+		left = variable
+
+	case tokenOperator:
+		currentOperator := p.tokenCurrent.valueLiteral
+
+		if !isPrefixOperator(currentOperator) {
+			p.errorf(
+				CodeUnexpectedToken,
+				"unexpected operator in expression: %s",
+				currentOperator,
+			)
+
+			return nil
+		}
+
+		p.advanceToken()
+
+		precedenceOperand := precedencePrefix
+		if currentOperator == _dslNot {
+			precedenceOperand = precedenceNot
+		}
+
+		operand := p.parseExpression(precedenceOperand)
+		if operand == nil {
+			return nil
+		}
+
+		left = &ExpressionUnary{
+			Span:     p.spanFrom(start),
+			Operator: currentOperator,
+			Operand:  operand,
+		}
+
+	case tokenIf:
+		left = p.parseConditional()
+		if left == nil {
+			return nil
+		}
+
+	case tokenCase:
+		left = p.parseCase()
+		if left == nil {
+			return nil
+		}
+
+	case tokenLeftBracket:
+		left = p.parseList()
+		if left == nil {
+			return nil
+		}
 
+	case tokenLeftParen:
 		p.advanceToken()
 
+		left = p.parseExpression(0)
+		if left == nil {
+			return nil
+		}
+
+		if !p.expectWTokenAdvance(
+			&paramsExpect{
+				KindExpected: tokenRightParen,
+			},
+		) {
+			return nil
+		}
+
 	default:
 		p.errorf(
-			"unexpected token in expression: %v (%s)",
-			p.tokenCurrent.kind,
-			p.tokenCurrent.valueLiteral,
+			CodeUnexpectedToken,
+			"unexpected %s in expression",
+			p.tokenCurrent.describe(),
 		)
 
 		return nil
@@ -76,22 +213,400 @@ func (p *parser) parseExpression(precedence int) expression {
 			break
 		}
 
+		isNegated := p.isNegatedMembership()
+		if isNegated {
+			p.advanceToken() // 'not'
+		}
+
 		currentOperator := p.tokenCurrent.valueLiteral
-		opPrec := p.operatorPrecedence(currentOperator)
+		opPrec := operatorPrecedence(currentOperator)
 
 		p.advanceToken()
 
+		if currentOperator == _dslBetween {
+			left = p.parseBetween(left, isNegated, opPrec, start)
+			if left == nil {
+				return nil
+			}
+
+			continue
+		}
+
+		if isNegated {
+			currentOperator = _dslNotIn
+		}
+
+		positionRight := p.tokenCurrent.pos
+
 		right := p.parseExpression(opPrec)
 		if right == nil {
 			return nil
 		}
 
-		left = &expressionBinary{
+		binary := ExpressionBinary{
 			LefthandSide:  left,
 			Operator:      currentOperator,
 			RighthandSide: right,
 		}
+
+		if currentOperator == _dslMatches {
+			binary.pattern = p.compilePattern(right, positionRight)
+			if binary.pattern == nil {
+				return nil
+			}
+		}
+
+		// 'within' belongs to the nearest enclosing equality, operands
+		// of higher precedence leave it in place for their caller.
+		if isEqualityOperator(currentOperator) && p.currentTokenIs(tokenWithin) {
+			p.advanceToken()
+
+			binary.Tolerance = p.parseExpression(opPrec)
+			if binary.Tolerance == nil {
+				return nil
+			}
+		}
+
+		binary.Span = p.spanFrom(start)
+		left = &binary
 	}
 
 	return left // return just the literal or variable if no operator follows
 }
+
+func (p *parser) parseCall() Expression {
+	result := ExpressionCall{
+		Name: p.tokenCurrent.valueLiteral,
+	}
+
+	start := p.tokenCurrent.pos
+
+	function, exists := p.functions.lookup(result.Name)
+	if !exists && !p.isSyntaxOnly {
+		p.errorf(
+			CodeUnknownFunction,
+			"unknown function '%s'",
+			result.Name,
+		)
+
+		return nil
+	}
+
+	result.function = function
+
+	p.advanceToken() // name
+	p.advanceToken() // '('
+
+	for !p.currentTokenIs(tokenRightParen) {
+		positionArgument := p.tokenCurrent.pos
+
+		argument := p.parseExpression(0)
+		if argument == nil {
+			return nil
+		}
+
+		// literal arguments can be checked against the signature right away.
+		if literal, isLiteral := argument.(*ExpressionLiteral); isLiteral && function != nil {
+			kindExpected := function.parameterKind(len(result.Arguments))
+
+			if !kindAccepts(kindExpected, literal.Value.Kind()) {
+				p.errorAt(
+					CodeArgumentKind,
+					positionArgument,
+					p.endPrevious,
+					"argument %d of '%s' must be %s, got %s",
+					len(result.Arguments)+1,
+					result.Name,
+					kindExpected,
+					literal.Value.Kind(),
+				)
+
+				return nil
+			}
+		}
+
+		result.Arguments = append(result.Arguments, argument)
+
+		if !p.currentTokenIs(tokenComma) {
+			break
+		}
+
+		p.advanceToken()
+
+		if p.currentTokenIs(tokenRightParen) {
+			p.errorf(
+				CodeUnexpectedToken,
+				"expected an argument after ',', got %s",
+				p.tokenCurrent.describe(),
+			)
+
+			return nil
+		}
+	}
+
+	if !p.expectWTokenAdvance(
+		&paramsExpect{
+			KindExpected: tokenRightParen,
+		},
+	) {
+		return nil
+	}
+
+	if function != nil && !function.acceptsArity(len(result.Arguments)) {
+		p.errorAt(
+			CodeArity,
+			start,
+			p.endPrevious,
+			"function '%s' does not accept %d argument(s)",
+			result.Name,
+			len(result.Arguments),
+		)
+
+		return nil
+	}
+
+	result.Span = p.spanFrom(start)
+
+	return &result
+}
+
+// compilePattern compiles the right side of 'matches' once, at parse time.
+func (p *parser) compilePattern(right Expression, position scanner.Position) *regexp.Regexp {
+	literal, isLiteral := right.(*ExpressionLiteral)
+	if !isLiteral || literal.Value.kind != KindString {
+		p.errorAt(
+			CodeInvalidPattern,
+			position,
+			p.endPrevious,
+			"right side of '%s' must be a string literal pattern",
+			_dslMatches,
+		)
+
+		return nil
+	}
+
+	result, errCompile := regexp.Compile(literal.Value.text)
+	if errCompile != nil {
+		p.errorAt(
+			CodeInvalidPattern,
+			position,
+			p.endPrevious,
+			"invalid pattern %s: %v",
+			literal.Raw,
+			errCompile,
+		)
+
+		return nil
+	}
+
+	return result
+}
+
+func (p *parser) parseList() Expression {
+	var result ExpressionList
+
+	start := p.tokenCurrent.pos
+
+	p.advanceToken() // '['
+
+	for !p.currentTokenIs(tokenRightBracket) {
+		element := p.parseExpression(0)
+		if element == nil {
+			return nil
+		}
+
+		result.Elements = append(result.Elements, element)
+
+		if !p.currentTokenIs(tokenComma) {
+			break
+		}
+
+		p.advanceToken()
+
+		if p.currentTokenIs(tokenRightBracket) {
+			p.errorf(
+				CodeUnexpectedToken,
+				"expected an element after ',', got %s",
+				p.tokenCurrent.describe(),
+			)
+
+			return nil
+		}
+	}
+
+	if !p.expectWTokenAdvance(
+		&paramsExpect{
+			KindExpected: tokenRightBracket,
+		},
+	) {
+		return nil
+	}
+
+	result.Span = p.spanFrom(start)
+
+	return &result
+}
+
+// parseBetween parses the bounds of 'operand between lower and upper',
+// the 'between' keyword being already consumed. Bounds bind like
+// comparison operands so the 'and' separator is not read as a connective.
+func (p *parser) parseBetween(operand Expression, isNegated bool, precedence int, start scanner.Position) Expression {
+	result := ExpressionBetween{
+		Operand:   operand,
+		IsNegated: isNegated,
+	}
+
+	result.Lower = p.parseExpression(precedence)
+	if result.Lower == nil {
+		return nil
+	}
+
+	if !p.currentTokenIs(tokenOperator) || p.tokenCurrent.valueLiteral != _dslAnd {
+		p.errorf(
+			CodeUnexpectedToken,
+			"expected '%s' between the bounds of '%s', got %s",
+			_dslAnd,
+			_dslBetween,
+			p.tokenCurrent.valueLiteral,
+		)
+
+		return nil
+	}
+
+	p.advanceToken()
+
+	result.Upper = p.parseExpression(precedence)
+	if result.Upper == nil {
+		return nil
+	}
+
+	result.Span = p.spanFrom(start)
+
+	return &result
+}
+
+// parseConditional parses 'if condition then a else b'. The alternative
+// extends as far as possible, like the lowest precedence operator.
+func (p *parser) parseConditional() Expression {
+	var result ExpressionConditional
+
+	start := p.tokenCurrent.pos
+
+	p.advanceToken() // 'if'
+
+	result.Condition = p.parseExpression(0)
+	if result.Condition == nil {
+		return nil
+	}
+
+	if !p.expectWTokenAdvance(
+		&paramsExpect{
+			KindExpected: tokenThen,
+		},
+	) {
+		return nil
+	}
+
+	result.Consequence = p.parseExpression(0)
+	if result.Consequence == nil {
+		return nil
+	}
+
+	if !p.expectWTokenAdvance(
+		&paramsExpect{
+			KindExpected: tokenElse,
+		},
+	) {
+		return nil
+	}
+
+	result.Alternative = p.parseExpression(0)
+	if result.Alternative == nil {
+		return nil
+	}
+
+	result.Span = p.spanFrom(start)
+
+	return &result
+}
+
+// parseCase parses 'case when c1 then a when c2 then b else d end' into
+// nested conditionals. Without 'else' the value is null when no branch matches.
+func (p *parser) parseCase() Expression {
+	var branches []*ExpressionConditional
+
+	start := p.tokenCurrent.pos
+
+	p.advanceToken() // 'case'
+
+	for p.currentTokenIs(tokenWhen) {
+		branch := ExpressionConditional{
+			Span: Span{
+				Start: p.tokenCurrent.pos,
+			},
+		}
+
+		p.advanceToken()
+
+		branch.Condition = p.parseExpression(0)
+		if branch.Condition == nil {
+			return nil
+		}
+
+		if !p.expectWTokenAdvance(
+			&paramsExpect{
+				KindExpected: tokenThen,
+			},
+		) {
+			return nil
+		}
+
+		branch.Consequence = p.parseExpression(0)
+		if branch.Consequence == nil {
+			return nil
+		}
+
+		branches = append(branches, &branch)
+	}
+
+	if len(branches) == 0 {
+		p.errorf(
+			CodeUnexpectedToken,
+			"'%s' needs at least one '%s' branch",
+			_dslCase,
+			_dslWhen,
+		)
+
+		return nil
+	}
+
+	var alternative Expression = newLiteral(nullValue(), _dslNull)
+
+	if p.currentTokenIs(tokenElse) {
+		p.advanceToken()
+
+		alternative = p.parseExpression(0)
+		if alternative == nil {
+			return nil
+		}
+	}
+
+	if !p.expectWTokenAdvance(
+		&paramsExpect{
+			KindExpected: tokenEnd,
+		},
+	) {
+		return nil
+	}
+
+	// each branch spans up to 'end', the first one from 'case'.
+	branches[0].Start = start
+
+	for ix := len(branches) - 1; ix >= 0; ix-- {
+		branches[ix].End = p.endPrevious
+		branches[ix].Alternative = alternative
+		alternative = branches[ix]
+	}
+
+	return alternative
+}
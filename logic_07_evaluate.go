@@ -1,132 +1,205 @@
 package dslalert
 
 import (
+	"context"
 	"fmt"
-	"sort"
-	"strconv"
+	"math"
+	"regexp"
+	"slices"
 	"strings"
 
 	goerrors "github.com/TudorHulban/go-errors"
 )
 
-func evaluateExpression(expr expression, contextValue float64) (any, error) {
-	switch expressionType := expr.(type) {
-	case *expressionLiteral:
-		return expressionType.value, nil // Return literal value
+// rowContext binds the variables of a condition to the current CSV row:
+// 'value' is the monitored column, any other name is a column of the row.
+type rowContext struct {
+	columns map[string]int // column name | column number
+	record  []string
 
-	case *expressionVariable:
-		if expressionType.name == "value" {
-			return contextValue, nil // Substitute the special 'value' variable
-		}
+	value Value
+}
 
-		return nil,
+func (row *rowContext) column(name string) (Value, error) {
+	columnIx, exists := row.columns[name]
+	if !exists {
+		return Value{},
 			fmt.Errorf(
-				"undefined variable '%s' (only 'value' is allowed)",
-				expressionType.name,
+				"undefined variable '%s' (not 'value' nor a column of the dataset)",
+				name,
 			)
+	}
 
-	case *expressionBinary:
-		// Recursively evaluate left and right sides
-		valueLeft, errEvaluateLeft := evaluateExpression(expressionType.LefthandSide, contextValue)
-		if errEvaluateLeft != nil {
-			return nil,
+	return parseCell(row.record[columnIx]), nil
+}
+
+func evaluateExpression(expr Expression, row *rowContext) (Value, error) {
+	switch expressionType := expr.(type) {
+	case *ExpressionLiteral:
+		return expressionType.Value, nil // Return literal value
+
+	case *ExpressionVariable:
+		if expressionType.Name == _dslValue {
+			return row.value, nil // Substitute the special 'value' variable
+		}
+
+		return row.column(expressionType.Name)
+
+	case *ExpressionUnary:
+		valueOperand, errEvaluateOperand := evaluateExpression(expressionType.Operand, row)
+		if errEvaluateOperand != nil {
+			return Value{},
 				fmt.Errorf(
-					"failed to evaluate left side of '%s': %w",
+					"failed to evaluate operand of '%s': %w",
 					expressionType.Operator,
-					errEvaluateLeft,
+					errEvaluateOperand,
 				)
 		}
 
-		valueRight, errEvaluateRight := evaluateExpression(expressionType.RighthandSide, contextValue)
-		if errEvaluateRight != nil {
-			return nil,
+		return unaryResult(expressionType.Operator, valueOperand)
+
+	case *ExpressionCall:
+		// calls built outside Parse, e.g. by Transform, are not resolved.
+		if expressionType.function == nil {
+			return Value{},
 				fmt.Errorf(
-					"failed to evaluate right side of '%s': %w",
-					expressionType.Operator,
-					errEvaluateRight,
+					"function '%s' is not resolved",
+					expressionType.Name,
 				)
 		}
 
-		// Perform Operation (numeric focus, same as before)
-		floatLeft, leftOk := toFloat64(valueLeft)
-		floatRight, rightOk := toFloat64(valueRight)
+		if expressionType.function.implementationNumeric == nil {
+			return evaluateCallHost(expressionType, row)
+		}
 
-		if isComparisonOperator(expressionType.Operator) {
-			if !leftOk || !rightOk {
-				return nil,
+		arguments := make([]float64, len(expressionType.Arguments))
+
+		for ix, argument := range expressionType.Arguments {
+			valueArgument, errEvaluateArgument := evaluateExpression(argument, row)
+			if errEvaluateArgument != nil {
+				return Value{},
 					fmt.Errorf(
-						"cannot compare non-numeric values ('%v' %s '%v')",
-						valueLeft,
-						expressionType.Operator,
-						valueRight,
+						"failed to evaluate argument %d of '%s': %w",
+						ix+1,
+						expressionType.Name,
+						errEvaluateArgument,
 					)
 			}
 
-			switch expressionType.Operator {
-			case ">":
-				return floatLeft > floatRight, nil
-			case ">=":
-				return floatLeft >= floatRight, nil
-			case "<":
-				return floatLeft < floatRight, nil
-			case "<=":
-				return floatLeft <= floatRight, nil
-			case "==":
-				return floatLeft == floatRight, nil
-			case "!=":
-				return floatLeft != floatRight, nil
-
-			default:
-				return nil,
-					fmt.Errorf(
-						"unsupported comparison operator '%s'",
-						expressionType.Operator,
-					)
+			number, errArgument := numericArgument(expressionType, ix, valueArgument)
+			if errArgument != nil {
+				return Value{}, errArgument
 			}
+
+			arguments[ix] = number
 		}
 
-		if isArithmeticOperator(expressionType.Operator) {
-			if !leftOk || !rightOk {
-				return nil,
+		return callNumeric(expressionType, arguments)
+
+	case *ExpressionList:
+		elements := make([]Value, len(expressionType.Elements))
+
+		for ix, element := range expressionType.Elements {
+			valueElement, errEvaluateElement := evaluateExpression(element, row)
+			if errEvaluateElement != nil {
+				return Value{},
 					fmt.Errorf(
-						"cannot perform arithmetic on non-numeric values ('%v' %s '%v')",
-						valueLeft,
-						expressionType.Operator,
-						valueRight,
+						"failed to evaluate list element %d: %w",
+						ix+1,
+						errEvaluateElement,
 					)
 			}
 
-			switch expressionType.Operator {
-			case "+":
-				return floatLeft + floatRight, nil
-			case "-":
-				return floatLeft - floatRight, nil
-			case "*":
-				return floatLeft * floatRight, nil
-			case "/":
-				if floatRight == 0 {
-					return nil, fmt.Errorf("division by zero")
-				}
+			elements[ix] = valueElement
+		}
 
-				return floatLeft / floatRight, nil
+		return listValue(elements), nil
 
-			default:
-				return nil,
-					fmt.Errorf(
-						"unsupported arithmetic operator '%s'",
-						expressionType.Operator,
-					)
+	case *ExpressionBetween:
+		return evaluateBetween(expressionType, row)
+
+	case *ExpressionConditional:
+		valueCondition, errEvaluateCondition := evaluateExpression(expressionType.Condition, row)
+		if errEvaluateCondition != nil {
+			return Value{},
+				fmt.Errorf(
+					"failed to evaluate condition of '%s': %w",
+					_dslIf,
+					errEvaluateCondition,
+				)
+		}
+
+		isConsequence, errCondition := conditionalChoice(valueCondition)
+		if errCondition != nil {
+			return Value{}, errCondition
+		}
+
+		// only the chosen branch is evaluated.
+		if isConsequence {
+			return evaluateExpression(expressionType.Consequence, row)
+		}
+
+		return evaluateExpression(expressionType.Alternative, row)
+
+	case *ExpressionBinary:
+		// Recursively evaluate left and right sides
+		valueLeft, errEvaluateLeft := evaluateExpression(expressionType.LefthandSide, row)
+		if errEvaluateLeft != nil {
+			return Value{},
+				fmt.Errorf(
+					"failed to evaluate left side of '%s': %w",
+					expressionType.Operator,
+					errEvaluateLeft,
+				)
+		}
+
+		if isLogicalOperator(expressionType.Operator) {
+			return evaluateLogical(expressionType, valueLeft, row)
+		}
+
+		valueRight, errEvaluateRight := evaluateExpression(expressionType.RighthandSide, row)
+		if errEvaluateRight != nil {
+			return Value{},
+				fmt.Errorf(
+					"failed to evaluate right side of '%s': %w",
+					expressionType.Operator,
+					errEvaluateRight,
+				)
+		}
+
+		if isEqualityOperator(expressionType.Operator) {
+			isEqual, errEqual := evaluateEquality(expressionType, valueLeft, valueRight, row)
+			if errEqual != nil {
+				return Value{}, errEqual
 			}
+
+			return boolValue(isEqual == (expressionType.Operator == "==")), nil
 		}
 
-		return nil,
+		if expressionType.Operator == _dslIn || expressionType.Operator == _dslNotIn {
+			return evaluateMembership(expressionType.Operator, valueLeft, valueRight)
+		}
+
+		if isStringOperator(expressionType.Operator) {
+			return evaluateMatching(expressionType.Operator, expressionType.pattern, valueLeft, valueRight)
+		}
+
+		if isComparisonOperator(expressionType.Operator) {
+			return evaluateOrdering(expressionType.Operator, valueLeft, valueRight)
+		}
+
+		if isArithmeticOperator(expressionType.Operator) {
+			return evaluateArithmetic(expressionType.Operator, valueLeft, valueRight)
+		}
+
+		return Value{},
 			fmt.Errorf(
 				"unsupported binary operator '%s'",
 				expressionType.Operator,
 			)
 
 	default:
-		return nil,
+		return Value{},
 			fmt.Errorf(
 				"unsupported expression type %T",
 				expr,
@@ -134,26 +207,558 @@ func evaluateExpression(expr expression, contextValue float64) (any, error) {
 	}
 }
 
-func evaluateCondition(expr expression, contextValue float64) (bool, error) {
-	result, errEvaluate := evaluateExpression(expr, contextValue)
+// unaryResult applies a unary operator, checking the kind of its operand.
+func unaryResult(operator string, valueOperand Value) (Value, error) {
+	switch operator {
+	case _dslNot, "!":
+		if valueOperand.kind != KindBool {
+			return Value{},
+				fmt.Errorf(
+					"cannot apply '%s' to %s %s (needs bool)",
+					operator,
+					valueOperand.kind,
+					valueOperand,
+				)
+		}
+
+		return boolValue(!valueOperand.boolean), nil
+
+	case "-", "+":
+		if valueOperand.kind != KindNumber {
+			return Value{},
+				fmt.Errorf(
+					"cannot apply '%s' to %s %s (needs number)",
+					operator,
+					valueOperand.kind,
+					valueOperand,
+				)
+		}
+
+		if operator == "-" {
+			return numberValue(-valueOperand.number), nil
+		}
+
+		return valueOperand, nil
+	}
+
+	return Value{},
+		fmt.Errorf(
+			"unsupported unary operator '%s'",
+			operator,
+		)
+}
+
+// evaluateChecked applies an arithmetic, ordering or string operator whose
+// operand kinds Check proved, skipping the runtime kind checks.
+func evaluateChecked(expr *ExpressionBinary, valueLeft, valueRight Value) (Value, error) {
+	if isStringOperator(expr.Operator) {
+		return evaluateMatching(expr.Operator, expr.pattern, valueLeft, valueRight)
+	}
+
+	if valueLeft.kind == KindString {
+		if expr.Operator == "+" {
+			return stringValue(valueLeft.text + valueRight.text), nil
+		}
+
+		return orderingResult(
+			expr.Operator,
+			strings.Compare(valueLeft.text, valueRight.text),
+		), nil
+	}
+
+	switch expr.Operator {
+	case "+":
+		return numberValue(valueLeft.number + valueRight.number), nil
+	case "-":
+		return numberValue(valueLeft.number - valueRight.number), nil
+	case "*":
+		return numberValue(valueLeft.number * valueRight.number), nil
+	case "/":
+		if valueRight.number == 0 {
+			return Value{}, fmt.Errorf("division by zero")
+		}
+
+		return numberValue(valueLeft.number / valueRight.number), nil
+
+	default:
+		return boolValue(orderNumbers(expr.Operator, valueLeft.number, valueRight.number)), nil
+	}
+}
+
+// orderingResult applies an ordering operator to the sign of a comparison.
+func orderingResult(operator string, order int) Value {
+	switch operator {
+	case ">":
+		return boolValue(order > 0)
+	case ">=":
+		return boolValue(order >= 0)
+	case "<":
+		return boolValue(order < 0)
+
+	default: // "<="
+		return boolValue(order <= 0)
+	}
+}
+
+// orderNumbers applies an ordering operator to two numbers as Go does:
+// every ordering involving NaN is false.
+func orderNumbers(operator string, a, b float64) bool {
+	switch operator {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+
+	default: // "<="
+		return a <= b
+	}
+}
+
+// evaluateOrdering compares two numbers or two strings (lexicographically).
+func evaluateOrdering(operator string, valueLeft, valueRight Value) (Value, error) {
+	if !isOrderingOperator(operator) {
+		return Value{},
+			fmt.Errorf(
+				"unsupported comparison operator '%s'",
+				operator,
+			)
+	}
+
+	switch {
+	case valueLeft.kind == KindNumber && valueRight.kind == KindNumber:
+		return boolValue(orderNumbers(operator, valueLeft.number, valueRight.number)), nil
+
+	case valueLeft.kind == KindString && valueRight.kind == KindString:
+		return orderingResult(operator, strings.Compare(valueLeft.text, valueRight.text)), nil
+
+	default:
+		return Value{},
+			fmt.Errorf(
+				"cannot order %s %s and %s %s with '%s' (needs two numbers or two strings)",
+				valueLeft.kind,
+				valueLeft,
+				valueRight.kind,
+				valueRight,
+				operator,
+			)
+	}
+}
+
+func evaluateMembership(operator string, valueLeft, valueRight Value) (Value, error) {
+	if valueRight.kind != KindList {
+		return Value{},
+			fmt.Errorf(
+				"right side of '%s' must be list, got %s %s",
+				operator,
+				valueRight.kind,
+				valueRight,
+			)
+	}
+
+	isMember := slices.ContainsFunc(
+		valueRight.list,
+		func(element Value) bool {
+			return valuesEqual(valueLeft, element)
+		},
+	)
+
+	return boolValue(isMember == (operator == _dslIn)), nil
+}
+
+// evaluateBetween tests lower <= operand <= upper, bounds included.
+func evaluateBetween(expr *ExpressionBetween, row *rowContext) (Value, error) {
+	var parts [3]Value
+
+	for ix, part := range []Expression{expr.Operand, expr.Lower, expr.Upper} {
+		valuePart, errEvaluatePart := evaluateExpression(part, row)
+		if errEvaluatePart != nil {
+			return Value{},
+				fmt.Errorf(
+					"failed to evaluate '%s': %w",
+					_dslBetween,
+					errEvaluatePart,
+				)
+		}
+
+		parts[ix] = valuePart
+	}
+
+	return betweenResult(expr.IsNegated, parts)
+}
+
+func betweenResult(isNegated bool, parts [3]Value) (Value, error) {
+	isAboveLower, errLower := evaluateOrdering(">=", parts[0], parts[1])
+	if errLower != nil {
+		return Value{}, errLower
+	}
+
+	isBelowUpper, errUpper := evaluateOrdering("<=", parts[0], parts[2])
+	if errUpper != nil {
+		return Value{}, errUpper
+	}
+
+	isBetween := isAboveLower.boolean && isBelowUpper.boolean
+
+	return boolValue(isBetween != isNegated), nil
+}
+
+// evaluateMatching applies contains, startswith, endswith and matches,
+// all of which need strings on both sides.
+func evaluateMatching(operator string, pattern *regexp.Regexp, valueLeft, valueRight Value) (Value, error) {
+	if valueLeft.kind != KindString || valueRight.kind != KindString {
+		return Value{},
+			fmt.Errorf(
+				"cannot apply '%s' to %s %s and %s %s (needs two strings)",
+				operator,
+				valueLeft.kind,
+				valueLeft,
+				valueRight.kind,
+				valueRight,
+			)
+	}
+
+	switch operator {
+	case _dslContains:
+		return boolValue(strings.Contains(valueLeft.text, valueRight.text)), nil
+	case _dslStartsWith:
+		return boolValue(strings.HasPrefix(valueLeft.text, valueRight.text)), nil
+	case _dslEndsWith:
+		return boolValue(strings.HasSuffix(valueLeft.text, valueRight.text)), nil
+	case _dslMatches:
+		if pattern == nil {
+			return Value{},
+				fmt.Errorf(
+					"pattern of '%s' is not compiled",
+					_dslMatches,
+				)
+		}
+
+		return boolValue(pattern.MatchString(valueLeft.text)), nil
+
+	default:
+		return Value{},
+			fmt.Errorf(
+				"unsupported string operator '%s'",
+				operator,
+			)
+	}
+}
+
+func evaluateArithmetic(operator string, valueLeft, valueRight Value) (Value, error) {
+	if operator == "+" && valueLeft.kind == KindString && valueRight.kind == KindString {
+		return stringValue(valueLeft.text + valueRight.text), nil
+	}
+
+	if valueLeft.kind != KindNumber || valueRight.kind != KindNumber {
+		return Value{},
+			fmt.Errorf(
+				"cannot perform arithmetic on %s %s and %s %s with '%s' (needs two numbers)",
+				valueLeft.kind,
+				valueLeft,
+				valueRight.kind,
+				valueRight,
+				operator,
+			)
+	}
+
+	switch operator {
+	case "+":
+		return numberValue(valueLeft.number + valueRight.number), nil
+	case "-":
+		return numberValue(valueLeft.number - valueRight.number), nil
+	case "*":
+		return numberValue(valueLeft.number * valueRight.number), nil
+	case "/":
+		if valueRight.number == 0 {
+			return Value{}, fmt.Errorf("division by zero")
+		}
+
+		return numberValue(valueLeft.number / valueRight.number), nil
+
+	default:
+		return Value{},
+			fmt.Errorf(
+				"unsupported arithmetic operator '%s'",
+				operator,
+			)
+	}
+}
+
+func numericArgument(expr *ExpressionCall, ix int, valueArgument Value) (float64, error) {
+	if valueArgument.kind != KindNumber {
+		return 0,
+			fmt.Errorf(
+				"argument %d of '%s' must be number, got %s %s",
+				ix+1,
+				expr.Name,
+				valueArgument.kind,
+				valueArgument,
+			)
+	}
+
+	return valueArgument.number, nil
+}
+
+func callNumeric(expr *ExpressionCall, arguments []float64) (Value, error) {
+	result, errCall := expr.function.implementationNumeric(arguments)
+	if errCall != nil {
+		return Value{},
+			fmt.Errorf(
+				"function '%s': %w",
+				expr.Name,
+				errCall,
+			)
+	}
+
+	return numberValue(result), nil
+}
+
+// conditionalChoice tells whether 'if' takes its consequence.
+func conditionalChoice(valueCondition Value) (bool, error) {
+	if valueCondition.kind != KindBool {
+		return false,
+			fmt.Errorf(
+				"condition of '%s' must be bool, got %s %s",
+				_dslIf,
+				valueCondition.kind,
+				valueCondition,
+			)
+	}
+
+	return valueCondition.boolean, nil
+}
+
+// evaluateCallHost invokes a function registered by the host, checking
+// argument and result kinds against its declared signature.
+func evaluateCallHost(expr *ExpressionCall, row *rowContext) (Value, error) {
+	arguments := make([]any, len(expr.Arguments))
+
+	for ix, argument := range expr.Arguments {
+		valueArgument, errEvaluateArgument := evaluateExpression(argument, row)
+		if errEvaluateArgument != nil {
+			return Value{},
+				fmt.Errorf(
+					"failed to evaluate argument %d of '%s': %w",
+					ix+1,
+					expr.Name,
+					errEvaluateArgument,
+				)
+		}
+
+		if errArgument := hostArgument(expr, ix, valueArgument); errArgument != nil {
+			return Value{}, errArgument
+		}
+
+		arguments[ix] = valueArgument.any()
+	}
+
+	return callHost(expr, arguments)
+}
+
+// hostArgument checks an argument against the declared parameter kind.
+func hostArgument(expr *ExpressionCall, ix int, valueArgument Value) error {
+	kindExpected := expr.function.parameterKind(ix)
+
+	if !kindAccepts(kindExpected, valueArgument.kind) {
+		return fmt.Errorf(
+			"argument %d of '%s' must be %s, got %s %s",
+			ix+1,
+			expr.Name,
+			kindExpected,
+			valueArgument.kind,
+			valueArgument,
+		)
+	}
+
+	return nil
+}
+
+// callHost invokes the host implementation, checking the kind of its result.
+func callHost(expr *ExpressionCall, arguments []any) (Value, error) {
+	resultRaw, errCall := expr.function.implementation(arguments)
+	if errCall != nil {
+		return Value{},
+			fmt.Errorf(
+				"function '%s': %w",
+				expr.Name,
+				errCall,
+			)
+	}
+
+	result, isSupported := valueFromAny(resultRaw)
+	if !isSupported || !kindAccepts(expr.function.result, result.kind) {
+		return Value{},
+			fmt.Errorf(
+				"function '%s' must return %s, got %T ('%v')",
+				expr.Name,
+				expr.function.result,
+				resultRaw,
+				resultRaw,
+			)
+	}
+
+	return result, nil
+}
+
+// evaluateEquality compares values of the same kind, values of different
+// kinds are never equal. Numbers compare exactly, unless a 'within' clause
+// gives a tolerance.
+func evaluateEquality(expr *ExpressionBinary, valueLeft, valueRight Value, row *rowContext) (bool, error) {
+	if expr.Tolerance != nil {
+		return evaluateEqualityWithin(expr, valueLeft, valueRight, row)
+	}
+
+	return valuesEqual(valueLeft, valueRight), nil
+}
+
+func valuesEqual(valueLeft, valueRight Value) bool {
+	if valueLeft.kind != valueRight.kind {
+		return false
+	}
+
+	switch valueLeft.kind {
+	case KindNumber:
+		return valueLeft.number == valueRight.number
+	case KindString:
+		return valueLeft.text == valueRight.text
+	case KindBool:
+		return valueLeft.boolean == valueRight.boolean
+	case KindList:
+		return slices.EqualFunc(valueLeft.list, valueRight.list, valuesEqual)
+
+	default:
+		return true // null == null
+	}
+}
+
+func evaluateEqualityWithin(expr *ExpressionBinary, valueLeft, valueRight Value, row *rowContext) (bool, error) {
+	valueTolerance, errEvaluate := evaluateExpression(expr.Tolerance, row)
+	if errEvaluate != nil {
+		return false,
+			fmt.Errorf(
+				"failed to evaluate tolerance of '%s': %w",
+				expr.Operator,
+				errEvaluate,
+			)
+	}
+
+	return equalWithin(expr.Operator, valueLeft, valueRight, valueTolerance)
+}
+
+func equalWithin(operator string, valueLeft, valueRight, valueTolerance Value) (bool, error) {
+	if valueLeft.kind != KindNumber || valueRight.kind != KindNumber {
+		return false,
+			fmt.Errorf(
+				"'%s' needs two numbers, got %s %s and %s %s",
+				_dslWithin,
+				valueLeft.kind,
+				valueLeft,
+				valueRight.kind,
+				valueRight,
+			)
+	}
+
+	if valueTolerance.kind != KindNumber || valueTolerance.number < 0 {
+		return false,
+			fmt.Errorf(
+				"tolerance of '%s' must be a non-negative number, got %s",
+				operator,
+				valueTolerance,
+			)
+	}
+
+	return math.Abs(valueLeft.number-valueRight.number) <= valueTolerance.number, nil
+}
+
+// evaluateLogical short-circuits 'and' / 'or': the right side is only
+// evaluated when the left side does not already decide the result.
+func evaluateLogical(expr *ExpressionBinary, valueLeft Value, row *rowContext) (Value, error) {
+	result, isDecided, errLeft := logicalLeft(expr.Operator, valueLeft)
+	if isDecided || errLeft != nil {
+		return result, errLeft
+	}
+
+	valueRight, errEvaluateRight := evaluateExpression(expr.RighthandSide, row)
+	if errEvaluateRight != nil {
+		return Value{},
+			fmt.Errorf(
+				"failed to evaluate right side of '%s': %w",
+				expr.Operator,
+				errEvaluateRight,
+			)
+	}
+
+	return logicalRight(expr.Operator, valueRight)
+}
+
+// logicalLeft decides 'and' / 'or' from the left side alone when it can,
+// the second result is false when the right side is needed.
+func logicalLeft(operator string, valueLeft Value) (Value, bool, error) {
+	if valueLeft.kind != KindBool {
+		return Value{},
+			false,
+			fmt.Errorf(
+				"left side of '%s' must be bool, got %s %s",
+				operator,
+				valueLeft.kind,
+				valueLeft,
+			)
+	}
+
+	if operator == _dslAnd && !valueLeft.boolean {
+		return boolValue(false), true, nil
+	}
+
+	if operator == _dslOr && valueLeft.boolean {
+		return boolValue(true), true, nil
+	}
+
+	return Value{}, false, nil
+}
+
+func logicalRight(operator string, valueRight Value) (Value, error) {
+	if valueRight.kind != KindBool {
+		return Value{},
+			fmt.Errorf(
+				"right side of '%s' must be bool, got %s %s",
+				operator,
+				valueRight.kind,
+				valueRight,
+			)
+	}
+
+	return valueRight, nil
+}
+
+func evaluateCondition(expr Expression, row *rowContext) (bool, error) {
+	result, errEvaluate := evaluateExpression(expr, row)
 	if errEvaluate != nil {
 		return false,
 			errEvaluate
 	}
 
-	resultBoolean, couldCast := result.(bool)
-	if !couldCast {
+	return conditionResult(result)
+}
+
+func conditionResult(result Value) (bool, error) {
+	if result.kind != KindBool {
 		return false,
 			fmt.Errorf(
-				"condition expression did not evaluate to a boolean, got %T",
+				"condition expression did not evaluate to a boolean, got %s %s",
+				result.kind,
 				result,
 			)
 	}
 
-	return resultBoolean, nil
+	return result.boolean, nil
 }
 
-func EvaluateCriteria(criteria *criteria, dataset []string) (EvaluationResults, error) {
+// EvaluateCriteria evaluates the criteria over a CSV dataset, header first.
+// Rows it cannot evaluate fully are skipped quietly: lines not split in as
+// many fields as the header, and rules whose condition fails on the row.
+func EvaluateCriteria(criteria *Criteria, dataset []string) (EvaluationResults, error) {
 	if criteria == nil {
 		return nil,
 			goerrors.ErrValidation{
@@ -164,73 +769,134 @@ func EvaluateCriteria(criteria *criteria, dataset []string) (EvaluationResults,
 			}
 	}
 
+	return compileCriteria(criteria).evaluate(dataset)
+}
+
+// evaluate runs the compiled rules over the rows of a CSV dataset, header
+// first, skipping the rows failing.
+func (c *criteriaCompiled) evaluate(dataset []string) (EvaluationResults, error) {
+	rows, errDataset := newDatasetRows("EvaluateCriteria", dataset)
+	if errDataset != nil {
+		return nil, errDataset
+	}
+
+	results, _, errEvaluate := c.evaluateRows(context.Background(), rows)
+
+	return results, errEvaluate
+}
+
+// datasetRows is a CSV dataset split once, read by any number of criteria.
+type datasetRows struct {
+	lines []string
+
+	columns map[string]int // column name | column number
+	records [][]string     // per line, nil for the header and the skipped lines
+	skipped map[int]error  // line number | why the line is skipped
+}
+
+func newDatasetRows(caller string, dataset []string) (*datasetRows, error) {
 	if len(dataset) == 0 {
 		return nil,
 			goerrors.ErrValidation{
-				Caller: "EvaluateCriteria",
+				Caller: caller,
 				Issue: goerrors.ErrNilInput{
 					InputName: "dataset",
 				},
 			}
 	}
 
-	// column name | column number
-	mapHeaderColumns := make(map[string]int, 0)
-
 	header := strings.Split(dataset[0], ",")
 
-	for ix, nameColumn := range header {
-		mapHeaderColumns[nameColumn] = ix
+	result := datasetRows{
+		lines:   dataset,
+		columns: make(map[string]int, len(header)),
+		records: make([][]string, len(dataset)),
+		skipped: make(map[int]error),
 	}
 
-	var results []EvaluationResult
-	rowIndex := 1
+	for ix, nameColumn := range header {
+		result.columns[nameColumn] = ix
+	}
 
-	for rowIndex < len(dataset) {
+	for rowIndex := 1; rowIndex < len(dataset); rowIndex++ {
 		record := strings.Split(dataset[rowIndex], ",")
 
-		if len(record) != len(mapHeaderColumns) {
-			fmt.Printf(
-				"Warning: Number fields row %d is different than header '%d'\n",
-				rowIndex,
-				len(mapHeaderColumns),
+		if len(record) != len(result.columns) {
+			result.skipped[rowIndex] = fmt.Errorf(
+				"%d fields, the header has %d",
+				len(record),
+				len(result.columns),
 			)
 
 			continue
 		}
 
-		for _, monitor := range criteria.Monitors {
-			columnIx, exists := mapHeaderColumns[monitor.ColumnName]
-			if !exists {
-				continue
+		result.records[rowIndex] = record
+	}
+
+	return &result, nil
+}
+
+// evaluateRows runs the compiled rules over the rows of the dataset.
+// For each monitor the first matching rule, the one of the highest level,
+// gives the result of the row. The rows failing are returned as errors
+// of the evaluation. It stops early when the context is done.
+func (c *criteriaCompiled) evaluateRows(ctx context.Context, dataset *datasetRows) (EvaluationResults, EvaluationErrors, error) {
+	// one row context for the whole dataset, the compiled conditions
+	// read it without keeping it.
+	row := rowContext{
+		columns: dataset.columns,
+	}
+
+	var (
+		results []EvaluationResult
+		errs    EvaluationErrors
+	)
+
+	for rowIndex, record := range dataset.records {
+		if errContext := ctx.Err(); errContext != nil {
+			return nil, nil, errContext
+		}
+
+		if record == nil {
+			if errSkipped, isSkipped := dataset.skipped[rowIndex]; isSkipped {
+				errs = append(
+					errs,
+
+					EvaluationError{
+						CriteriaName: c.name,
+						RowIndex:     rowIndex,
+						Err:          errSkipped,
+					},
+				)
 			}
 
-			valueRaw := record[columnIx]
+			continue
+		}
 
-			// for numeric comparison
-			valueCurrent, err := strconv.ParseFloat(valueRaw, 64)
-			if err != nil {
+		row.record = record
+
+		for _, monitor := range c.monitors {
+			columnIx, exists := dataset.columns[monitor.columnName]
+			if !exists {
 				continue
 			}
 
-			// Sort rules by level descending
-			sort.SliceStable(
-				monitor.Rules,
-				func(i, j int) bool {
-					return monitor.Rules[i].Level > monitor.Rules[j].Level
-				},
-			)
+			row.value = parseCell(record[columnIx])
 
-			for _, rule := range monitor.Rules {
-				match, errEvaluate := evaluateCondition(rule.Condition, valueCurrent)
+			for _, rule := range monitor.rules {
+				match, errEvaluate := rule.condition(&row)
 				if errEvaluate != nil {
-					fmt.Printf(
-						"Warning: Row %d, Criteria '%s', Monitor '%s': Error evaluating condition for level %d: %v\n",
-						rowIndex,
-						criteria.Name,
-						monitor.ColumnName,
-						rule.Level,
-						errEvaluate,
+					errs = append(
+						errs,
+
+						EvaluationError{
+							CriteriaName: c.name,
+							MonitorName:  monitor.columnName,
+							RowIndex:     rowIndex,
+							RuleLevel:    rule.level,
+							Err:          errEvaluate,
+						},
 					)
 
 					continue
@@ -238,14 +904,18 @@ func EvaluateCriteria(criteria *criteria, dataset []string) (EvaluationResults,
 
 				if match {
 					result := EvaluationResult{
-						CriteriaName: criteria.Name,
-						MonitorName:  monitor.ColumnName,
-						Row:          dataset[rowIndex],
+						CriteriaName: c.name,
+						MonitorName:  monitor.columnName,
+						Row:          dataset.lines[rowIndex],
+
+						CriteriaDoc: c.doc,
+						MonitorDoc:  monitor.doc,
+						RuleDoc:     rule.doc,
 
 						RowIndex:  rowIndex,
-						RuleLevel: rule.Level,
+						RuleLevel: rule.level,
 
-						ValueCurrent: valueCurrent,
+						ValueCurrent: row.value.any(),
 					}
 
 					results = append(results, result)
@@ -254,10 +924,9 @@ func EvaluateCriteria(criteria *criteria, dataset []string) (EvaluationResults,
 				}
 			}
 		}
-
-		rowIndex++
 	}
 
 	return results,
+		errs,
 		nil
 }
@@ -0,0 +1,104 @@
+package dslalert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Render prints the diagnostics against the source they were reported for,
+// each with the offending line and its span underlined:
+//
+//	error[DSL004]: expected ;, got }
+//	 --> <input>:4:2
+//	  |
+//	4 | 	}
+//	  | 	^
+//	  = hint: ...
+func (d Diagnostics) Render(source string) string {
+	lines := strings.Split(source, "\n")
+
+	rendered := make([]string, len(d))
+
+	for ix, diagnostic := range d {
+		rendered[ix] = diagnostic.render(lines)
+	}
+
+	return strings.Join(rendered, "\n")
+}
+
+// Render prints the diagnostic against the source it was reported for.
+func (d Diagnostic) Render(source string) string {
+	return d.render(strings.Split(source, "\n"))
+}
+
+func (d Diagnostic) render(lines []string) string {
+	var result strings.Builder
+
+	fmt.Fprintf(
+		&result,
+		"%s[%s]: %s\n",
+
+		d.Severity,
+		d.Code,
+		d.Message,
+	)
+
+	gutter := strings.Repeat(" ", len(strconv.Itoa(d.Start.Line)))
+
+	if d.Start.IsValid() && d.Start.Line <= len(lines) {
+		line := strings.TrimRight(lines[d.Start.Line-1], "\r")
+
+		fmt.Fprintf(&result, "%s--> %s\n", gutter, d.Start)
+		fmt.Fprintf(&result, "%s |\n", gutter)
+		fmt.Fprintf(&result, "%d | %s\n", d.Start.Line, line)
+		fmt.Fprintf(&result, "%s | %s\n", gutter, d.underline(line))
+	}
+
+	if len(d.Expected) > 0 {
+		fmt.Fprintf(
+			&result,
+			"%s = expected: %s\n",
+
+			gutter,
+			strings.Join(d.Expected, ", "),
+		)
+	}
+
+	if len(d.Hint) > 0 {
+		fmt.Fprintf(&result, "%s = hint: %s\n", gutter, d.Hint)
+	}
+
+	return result.String()
+}
+
+// underline marks the span on its first line with carets, keeping the tabs
+// of the line so the carets stay aligned under the source.
+func (d Diagnostic) underline(line string) string {
+	runes := []rune(line)
+
+	ixStart := min(max(d.Start.Column-1, 0), len(runes))
+
+	ixEnd := len(runes)
+	if d.End.Line == d.Start.Line {
+		ixEnd = min(d.End.Column-1, len(runes))
+	}
+
+	var result strings.Builder
+
+	for _, ch := range runes[:ixStart] {
+		if ch == '\t' {
+			result.WriteRune('\t')
+
+			continue
+		}
+
+		result.WriteRune(' ')
+	}
+
+	result.WriteString(
+		strings.Repeat("^", max(ixEnd-ixStart, 1)),
+	)
+
+	return result.String()
+}
@@ -0,0 +1,242 @@
+package dslalert
+
+import "fmt"
+
+// Visitor is called by Walk for each node. When Visit returns a non nil
+// visitor w, Walk visits the children of the node with w, then calls
+// w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the tree depth-first in source order:
+// criteria, monitors, rules, then the condition of each rule.
+// A nil node is not visited.
+func Walk(v Visitor, node Node) {
+	if node == nil {
+		return
+	}
+
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	for _, child := range children(node) {
+		Walk(v, child)
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+
+	return nil
+}
+
+// Inspect traverses the tree like Walk, calling f for each node.
+// When f returns false the children of the node are skipped.
+// After the children f is called with nil.
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// children lists the direct children of a node, skipping absent ones
+// like the condition of a broken rule or a missing 'within' tolerance.
+func children(node Node) []Node {
+	var result []Node
+
+	add := func(nodes ...Node) {
+		for _, child := range nodes {
+			if child != nil {
+				result = append(result, child)
+			}
+		}
+	}
+
+	switch n := node.(type) {
+	case *AlertConfiguration:
+		for _, criteria := range n.Criterias {
+			add(criteria)
+		}
+
+	case *Criteria:
+		for _, monitor := range n.Monitors {
+			add(monitor)
+		}
+
+	case *Monitor:
+		for _, rule := range n.Rules {
+			add(rule)
+		}
+
+	case *Rule:
+		add(n.Condition)
+
+	case *ExpressionBinary:
+		add(n.LefthandSide, n.RighthandSide, n.Tolerance)
+
+	case *ExpressionUnary:
+		add(n.Operand)
+
+	case *ExpressionCall:
+		for _, argument := range n.Arguments {
+			add(argument)
+		}
+
+	case *ExpressionList:
+		for _, element := range n.Elements {
+			add(element)
+		}
+
+	case *ExpressionBetween:
+		add(n.Operand, n.Lower, n.Upper)
+
+	case *ExpressionConditional:
+		add(n.Condition, n.Consequence, n.Alternative)
+
+	case *ExpressionLiteral, *ExpressionVariable:
+		// leaves
+
+	default:
+		// nil and node types of other packages have no children to visit.
+	}
+
+	return result
+}
+
+// Transform rewrites the expressions under node bottom-up: f receives each
+// expression with its children already rewritten and returns the expression
+// to use instead, possibly the one received. Nodes are copied on the way,
+// the input tree is left unchanged.
+// The result has the type of node, except for an expression node which f
+// may replace with an expression of another type.
+//
+// Expressions created by f are not resolved against the function registry:
+// a call built by f cannot be evaluated, neither can a 'matches' whose
+// pattern f replaced.
+func Transform(node Node, f func(Expression) Expression) Node {
+	switch n := node.(type) {
+	case *AlertConfiguration:
+		result := *n
+		result.Criterias = make([]*Criteria, len(n.Criterias))
+
+		for ix, criteria := range n.Criterias {
+			result.Criterias[ix] = Transform(criteria, f).(*Criteria)
+		}
+
+		return &result
+
+	case *Criteria:
+		result := *n
+		result.Monitors = make([]*Monitor, len(n.Monitors))
+
+		for ix, monitor := range n.Monitors {
+			result.Monitors[ix] = Transform(monitor, f).(*Monitor)
+		}
+
+		return &result
+
+	case *Monitor:
+		result := *n
+		result.Rules = make([]*Rule, len(n.Rules))
+
+		for ix, rule := range n.Rules {
+			result.Rules[ix] = Transform(rule, f).(*Rule)
+		}
+
+		return &result
+
+	case *Rule:
+		result := *n
+		result.Condition = transformExpression(n.Condition, f)
+
+		return &result
+
+	case Expression:
+		return transformExpression(n, f)
+
+	default:
+		panic(
+			fmt.Sprintf("dslalert.Transform: unexpected node type %T", node),
+		)
+	}
+}
+
+func transformExpression(expression Expression, f func(Expression) Expression) Expression {
+	switch e := expression.(type) {
+	case nil:
+		return nil
+
+	case *ExpressionBinary:
+		result := *e
+		result.LefthandSide = transformExpression(e.LefthandSide, f)
+		result.RighthandSide = transformExpression(e.RighthandSide, f)
+		result.Tolerance = transformExpression(e.Tolerance, f)
+
+		return f(&result)
+
+	case *ExpressionUnary:
+		result := *e
+		result.Operand = transformExpression(e.Operand, f)
+
+		return f(&result)
+
+	case *ExpressionCall:
+		result := *e
+		result.Arguments = transformExpressions(e.Arguments, f)
+
+		return f(&result)
+
+	case *ExpressionList:
+		result := *e
+		result.Elements = transformExpressions(e.Elements, f)
+
+		return f(&result)
+
+	case *ExpressionBetween:
+		result := *e
+		result.Operand = transformExpression(e.Operand, f)
+		result.Lower = transformExpression(e.Lower, f)
+		result.Upper = transformExpression(e.Upper, f)
+
+		return f(&result)
+
+	case *ExpressionConditional:
+		result := *e
+		result.Condition = transformExpression(e.Condition, f)
+		result.Consequence = transformExpression(e.Consequence, f)
+		result.Alternative = transformExpression(e.Alternative, f)
+
+		return f(&result)
+
+	case *ExpressionLiteral:
+		result := *e
+
+		return f(&result)
+
+	case *ExpressionVariable:
+		result := *e
+
+		return f(&result)
+
+	default:
+		panic(
+			fmt.Sprintf("dslalert.Transform: unexpected expression type %T", expression),
+		)
+	}
+}
+
+func transformExpressions(expressions []Expression, f func(Expression) Expression) []Expression {
+	result := make([]Expression, len(expressions))
+
+	for ix, expression := range expressions {
+		result[ix] = transformExpression(expression, f)
+	}
+
+	return result
+}
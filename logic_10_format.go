@@ -0,0 +1,294 @@
+package dslalert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// precedenceAtom is the binding power of literals, variables, calls and
+// lists: they never need parentheses.
+const precedenceAtom = precedencePrefix + 1
+
+// Format prints the configuration as canonical DSL text: tab indentation,
+// one rule per line, a blank line between criteria and between monitors,
+// and only the parentheses the operator precedence needs.
+// The comments between criteria, monitors and rules are kept, as are those
+// on the lines of their braces and semicolons, each comment group separated
+// by a blank line. Other comments, e.g. inside a condition, are not part
+// of the tree.
+//
+// The configuration is expected free of error nodes. Parsing the result
+// gives back an equivalent configuration.
+func Format(configuration *AlertConfiguration) string {
+	var f formatter
+
+	for ix, criteria := range configuration.Criterias {
+		if ix > 0 {
+			f.result.WriteString("\n")
+		}
+
+		f.criteria(criteria)
+	}
+
+	if len(configuration.Criterias) > 0 && len(configuration.Dangling) > 0 {
+		f.result.WriteString("\n")
+	}
+
+	f.groups(configuration.Dangling)
+
+	return f.result.String()
+}
+
+// FormatExpression prints a condition with minimal parentheses.
+func FormatExpression(expression Expression) string {
+	return formatExpression(expression, 0)
+}
+
+type formatter struct {
+	result strings.Builder
+	depth  int
+}
+
+func (f *formatter) criteria(criteria *Criteria) {
+	f.detached(criteria.Detached)
+	f.doc(criteria.Doc)
+	f.line(
+		"%s %s {%s",
+		_dslCriteria,
+		strconv.Quote(criteria.Name),
+		formatTrailing(criteria.CommentOpening),
+	)
+
+	f.depth++
+
+	for ix, monitor := range criteria.Monitors {
+		if ix > 0 {
+			f.result.WriteString("\n")
+		}
+
+		f.monitor(monitor)
+	}
+
+	if len(criteria.Dangling) > 0 {
+		f.result.WriteString("\n")
+	}
+
+	f.groups(criteria.Dangling)
+
+	f.depth--
+
+	f.line("}%s", formatTrailing(criteria.Comment))
+}
+
+func (f *formatter) monitor(monitor *Monitor) {
+	f.detached(monitor.Detached)
+	f.doc(monitor.Doc)
+	f.line(
+		"%s %s {%s",
+		_dslMonitor,
+		strconv.Quote(monitor.ColumnName),
+		formatTrailing(monitor.CommentOpening),
+	)
+
+	f.depth++
+
+	for _, rule := range monitor.Rules {
+		f.detached(rule.Detached)
+		f.doc(rule.Doc)
+		f.line(
+			"%s %d %s %s;%s",
+			_dslLevel,
+			rule.Level,
+			_dslWhen,
+			FormatExpression(rule.Condition),
+			formatTrailing(rule.Comment),
+		)
+	}
+
+	f.groups(monitor.Dangling)
+
+	f.depth--
+
+	f.line("}%s", formatTrailing(monitor.Comment))
+}
+
+func (f *formatter) line(format string, args ...any) {
+	f.result.WriteString(strings.Repeat("\t", f.depth))
+	fmt.Fprintf(&f.result, format, args...)
+	f.result.WriteString("\n")
+}
+
+func (f *formatter) doc(doc string) {
+	if doc == "" {
+		return
+	}
+
+	f.comment(doc)
+}
+
+// comment prints a comment group as line comments.
+func (f *formatter) comment(text string) {
+	for line := range strings.SplitSeq(text, "\n") {
+		f.line("%s", strings.TrimSpace("// "+line))
+	}
+}
+
+// detached prints the comment groups above a doc, each followed by
+// the blank line keeping it detached.
+func (f *formatter) detached(groups []string) {
+	for _, group := range groups {
+		f.comment(group)
+		f.result.WriteString("\n")
+	}
+}
+
+// groups prints comment groups separated by blank lines.
+func (f *formatter) groups(groups []string) {
+	for ix, group := range groups {
+		if ix > 0 {
+			f.result.WriteString("\n")
+		}
+
+		f.comment(group)
+	}
+}
+
+// formatTrailing prints a trailing comment, as a block comment when it
+// spans several lines so it stays attached to the same token.
+func formatTrailing(comment string) string {
+	if comment == "" {
+		return ""
+	}
+
+	if strings.Contains(comment, "\n") {
+		return " /* " + comment + " */"
+	}
+
+	return " // " + comment
+}
+
+// formatExpression prints the expression, in parentheses when it binds
+// looser than its position requires.
+// Binary operators are left associative: the right operand has to bind
+// tighter than the operator, the left one at least as tight.
+func formatExpression(expression Expression, precedence int) string {
+	result, precedenceOwn := formatOperation(expression)
+
+	if precedenceOwn < precedence {
+		return "(" + result + ")"
+	}
+
+	return result
+}
+
+func formatOperation(expression Expression) (string, int) {
+	switch e := expression.(type) {
+	case *ExpressionBinary:
+		precedence := operatorPrecedence(e.Operator)
+
+		precedenceRight := precedence + 1
+
+		// 'not' reads its operand up to the next 'and' / 'or',
+		// so needs no parentheses on their right.
+		if unary, isUnary := e.RighthandSide.(*ExpressionUnary); isUnary &&
+			unary.Operator == _dslNot &&
+			precedence <= precedenceNot {
+			precedenceRight = precedenceNot
+		}
+
+		result := fmt.Sprintf(
+			"%s %s %s",
+
+			formatExpression(e.LefthandSide, precedence),
+			e.Operator,
+			formatExpression(e.RighthandSide, precedenceRight),
+		)
+
+		if e.Tolerance != nil {
+			result = fmt.Sprintf(
+				"%s %s %s",
+
+				result,
+				_dslWithin,
+				formatExpression(e.Tolerance, precedence+1),
+			)
+		}
+
+		return result, precedence
+
+	case *ExpressionBetween:
+		precedence := operatorPrecedence(_dslBetween)
+
+		operator := _dslBetween
+		if e.IsNegated {
+			operator = _dslNot + " " + _dslBetween
+		}
+
+		return fmt.Sprintf(
+				"%s %s %s %s %s",
+
+				formatExpression(e.Operand, precedence),
+				operator,
+				formatExpression(e.Lower, precedence+1),
+				_dslAnd,
+				formatExpression(e.Upper, precedence+1),
+			),
+			precedence
+
+	case *ExpressionUnary:
+		if e.Operator == _dslNot {
+			return e.Operator + " " + formatExpression(e.Operand, precedenceNot+1),
+				precedenceNot
+		}
+
+		return e.Operator + formatExpression(e.Operand, precedencePrefix+1),
+			precedencePrefix
+
+	case *ExpressionConditional:
+		// the alternative extends as far as possible, so a conditional
+		// is only left bare where nothing can follow it.
+		return fmt.Sprintf(
+				"%s %s %s %s %s %s",
+
+				_dslIf,
+				formatExpression(e.Condition, 0),
+				_dslThen,
+				formatExpression(e.Consequence, 0),
+				_dslElse,
+				formatExpression(e.Alternative, 0),
+			),
+			0
+
+	case *ExpressionCall:
+		return e.Name + "(" + formatExpressions(e.Arguments) + ")",
+			precedenceAtom
+
+	case *ExpressionList:
+		return "[" + formatExpressions(e.Elements) + "]",
+			precedenceAtom
+
+	case *ExpressionLiteral:
+		return e.Raw,
+			precedenceAtom
+
+	case *ExpressionVariable:
+		return e.Name,
+			precedenceAtom
+
+	default:
+		panic(
+			fmt.Sprintf("dslalert.Format: unexpected expression type %T", expression),
+		)
+	}
+}
+
+func formatExpressions(expressions []Expression) string {
+	result := make([]string, len(expressions))
+
+	for ix, expression := range expressions {
+		result[ix] = formatExpression(expression, 0)
+	}
+
+	return strings.Join(result, ", ")
+}
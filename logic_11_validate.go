@@ -0,0 +1,205 @@
+package dslalert
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ParamsValidate configures ValidateWithParams.
+type ParamsValidate struct {
+	// Columns are the columns of the datasets the configuration runs on.
+	// When set, monitors and variables must name one of them, otherwise
+	// any variable is accepted as a column of the row, with a warning
+	// when its name looks like a misspelled 'value'.
+	Columns []string
+}
+
+// Validate checks what the grammar cannot: unique criteria names, one
+// monitor per column in a criteria, unique non negative levels in a monitor,
+// then the kinds of the conditions through Check.
+// Error nodes of tolerant parsing are skipped.
+func Validate(configuration *AlertConfiguration) Diagnostics {
+	return ValidateWithParams(configuration, &ParamsValidate{})
+}
+
+func ValidateWithParams(configuration *AlertConfiguration, params *ParamsValidate) Diagnostics {
+	v := validator{
+		columns: params.Columns,
+		checker: newChecker(),
+	}
+
+	namesCriteria := make(map[string]bool, len(configuration.Criterias))
+
+	for _, criteria := range configuration.Criterias {
+		if criteria.IsError {
+			continue
+		}
+
+		if namesCriteria[criteria.Name] {
+			v.errorf(
+				criteria.Span,
+				CodeDuplicateCriteria,
+				"duplicate criteria '%s'",
+				criteria.Name,
+			)
+		}
+
+		namesCriteria[criteria.Name] = true
+
+		v.criteria(criteria)
+	}
+
+	result := append(v.diagnostics, v.checker.diagnostics...)
+
+	slices.SortStableFunc(
+		result,
+		func(a, b Diagnostic) int {
+			return a.Start.Offset - b.Start.Offset
+		},
+	)
+
+	return result
+}
+
+type validator struct {
+	columns []string
+	checker *checker
+
+	diagnostics Diagnostics
+}
+
+func (v *validator) errorf(span Span, code Code, format string, args ...any) {
+	v.diagnostics = append(
+		v.diagnostics,
+
+		Diagnostic{
+			Severity: SeverityError,
+			Code:     code,
+			Start:    span.Start,
+			End:      span.End,
+			Message:  fmt.Sprintf(format, args...),
+		},
+	)
+}
+
+func (v *validator) criteria(criteria *Criteria) {
+	columnsMonitored := make(map[string]bool, len(criteria.Monitors))
+
+	for _, monitor := range criteria.Monitors {
+		if monitor.IsError {
+			continue
+		}
+
+		if columnsMonitored[monitor.ColumnName] {
+			v.errorf(
+				monitor.Span,
+				CodeDuplicateMonitor,
+				"column '%s' is already monitored in criteria '%s'",
+				monitor.ColumnName,
+				criteria.Name,
+			)
+		}
+
+		columnsMonitored[monitor.ColumnName] = true
+
+		if v.columns != nil && !slices.Contains(v.columns, monitor.ColumnName) {
+			v.errorf(
+				monitor.Span,
+				CodeUndefinedVariable,
+				"monitored column '%s' is not a column of the dataset",
+				monitor.ColumnName,
+			)
+		}
+
+		v.monitor(monitor)
+	}
+}
+
+func (v *validator) monitor(monitor *Monitor) {
+	levels := make(map[int]bool, len(monitor.Rules))
+
+	for _, rule := range monitor.Rules {
+		if rule.IsError {
+			continue
+		}
+
+		if rule.Level < 0 {
+			v.errorf(
+				rule.Span,
+				CodeInvalidLevel,
+				"level %d must not be negative",
+				rule.Level,
+			)
+		}
+
+		if levels[rule.Level] {
+			v.errorf(
+				rule.Span,
+				CodeDuplicateLevel,
+				"duplicate level %d in monitor '%s'",
+				rule.Level,
+				monitor.ColumnName,
+			)
+		}
+
+		levels[rule.Level] = true
+
+		v.condition(rule.Condition)
+	}
+}
+
+func (v *validator) condition(condition Expression) {
+	v.checker.condition(condition)
+
+	Inspect(
+		condition,
+		func(node Node) bool {
+			variable, isVariable := node.(*ExpressionVariable)
+			if !isVariable || variable.Name == _dslValue {
+				return true
+			}
+
+			if v.columns == nil {
+				v.warnMisspelled(variable)
+
+				return true
+			}
+
+			if !slices.Contains(v.columns, variable.Name) {
+				v.errorf(
+					variable.Span,
+					CodeUndefinedVariable,
+					"undefined variable '%s' (not '%s' nor a column of the dataset)",
+					variable.Name,
+					_dslValue,
+				)
+			}
+
+			return true
+		},
+	)
+}
+
+// warnMisspelled reports a variable read as a column while its name is
+// one or two edits away from 'value', likely a typo evaluating to null.
+func (v *validator) warnMisspelled(variable *ExpressionVariable) {
+	if editDistance(variable.Name, _dslValue) > 2 {
+		return
+	}
+
+	v.diagnostics = append(
+		v.diagnostics,
+
+		Diagnostic{
+			Severity: SeverityWarning,
+			Code:     CodeUndefinedVariable,
+			Start:    variable.Start,
+			End:      variable.End,
+			Message: fmt.Sprintf(
+				"variable '%s' is read as a column of the row, no columns declared",
+				variable.Name,
+			),
+			Hint: fmt.Sprintf("did you mean `%s`?", _dslValue),
+		},
+	)
+}
@@ -0,0 +1,320 @@
+package dslalert
+
+import "fmt"
+
+// TypeInfo holds the kind Check inferred for each expression.
+// KindAny stands for a kind only known per row, e.g. of 'value'.
+type TypeInfo struct {
+	Kinds map[Expression]Kind
+
+	checked map[Expression]bool // operations whose operand kinds are proven
+}
+
+// KindOf returns the inferred kind of an expression, KindAny when unknown.
+func (info *TypeInfo) KindOf(expression Expression) Kind {
+	return info.Kinds[expression]
+}
+
+// isChecked tells whether the operand kinds of an operation are proven,
+// so evaluation can skip their runtime checks, see evaluateChecked.
+func (info *TypeInfo) isChecked(expression Expression) bool {
+	return info != nil && info.checked[expression]
+}
+
+// Check infers the kind of every expression of the rules and reports
+// operands of the wrong kind and conditions that are not bool.
+// The configuration is left unchanged. Error nodes of tolerant parsing
+// are skipped.
+func Check(configuration *AlertConfiguration) (*TypeInfo, Diagnostics) {
+	c := newChecker()
+
+	for _, criteria := range configuration.Criterias {
+		for _, monitor := range criteria.Monitors {
+			if monitor.IsError {
+				continue
+			}
+
+			for _, rule := range monitor.Rules {
+				if rule.IsError {
+					continue
+				}
+
+				c.condition(rule.Condition)
+			}
+		}
+	}
+
+	return c.info,
+		c.diagnostics
+}
+
+type checker struct {
+	info *TypeInfo
+
+	diagnostics Diagnostics
+}
+
+func newChecker() *checker {
+	return &checker{
+		info: &TypeInfo{
+			Kinds:   make(map[Expression]Kind),
+			checked: make(map[Expression]bool),
+		},
+	}
+}
+
+// checkCondition checks a condition on its own, e.g. once optimized,
+// for compiling it.
+func checkCondition(condition Expression) *TypeInfo {
+	c := newChecker()
+	c.condition(condition)
+
+	return c.info
+}
+
+func (c *checker) errorf(node Node, code Code, format string, args ...any) {
+	c.diagnostics = append(
+		c.diagnostics,
+
+		Diagnostic{
+			Severity: SeverityError,
+			Code:     code,
+			Start:    node.Location().Start,
+			End:      node.Location().End,
+			Message:  fmt.Sprintf(format, args...),
+		},
+	)
+}
+
+func (c *checker) condition(condition Expression) {
+	if kind := c.expression(condition); kind != KindBool && kind != KindAny {
+		c.errorf(
+			condition,
+			CodeConditionNotBoolean,
+			"condition must be a bool, got %s",
+			kind,
+		)
+	}
+}
+
+// expect reports an operand whose known kind is not the wanted one.
+func (c *checker) expect(operand Expression, kind, kindWanted Kind, role string) bool {
+	if kindAccepts(kindWanted, kind) {
+		return true
+	}
+
+	c.errorf(
+		operand,
+		CodeTypeMismatch,
+		"%s must be %s, got %s",
+		role,
+		kindWanted,
+		kind,
+	)
+
+	return false
+}
+
+func (c *checker) expression(expression Expression) Kind {
+	kind := c.infer(expression)
+
+	c.info.Kinds[expression] = kind
+
+	return kind
+}
+
+func (c *checker) infer(expression Expression) Kind {
+	switch e := expression.(type) {
+	case *ExpressionLiteral:
+		return e.Value.Kind()
+
+	case *ExpressionVariable:
+		return KindAny
+
+	case *ExpressionList:
+		for _, element := range e.Elements {
+			c.expression(element)
+		}
+
+		return KindList
+
+	case *ExpressionUnary:
+		kindOperand := c.expression(e.Operand)
+
+		kindWanted := KindBool
+		if e.Operator == "-" || e.Operator == "+" {
+			kindWanted = KindNumber
+		}
+
+		c.info.checked[e] = kindOperand == kindWanted
+
+		c.expect(e.Operand, kindOperand, kindWanted, fmt.Sprintf("operand of '%s'", e.Operator))
+
+		return kindWanted
+
+	case *ExpressionBinary:
+		return c.binary(e)
+
+	case *ExpressionBetween:
+		kindOperand := c.expression(e.Operand)
+		kindLower := c.expression(e.Lower)
+		kindUpper := c.expression(e.Upper)
+
+		// bounds of different kinds leave no value in between,
+		// one report per expression is enough.
+		_ = c.ordered(e, _dslBetween, kindLower, kindUpper) &&
+			c.ordered(e, _dslBetween, kindOperand, kindLower) &&
+			c.ordered(e, _dslBetween, kindOperand, kindUpper)
+
+		return KindBool
+
+	case *ExpressionCall:
+		for ix, argument := range e.Arguments {
+			kindArgument := c.expression(argument)
+
+			if e.function != nil {
+				c.expect(
+					argument,
+					kindArgument,
+					e.function.parameterKind(ix),
+					fmt.Sprintf("argument %d of '%s'", ix+1, e.Name),
+				)
+			}
+		}
+
+		if e.function == nil {
+			return KindAny
+		}
+
+		return e.function.result
+
+	case *ExpressionConditional:
+		c.expect(e.Condition, c.expression(e.Condition), KindBool, "condition of 'if'")
+
+		kindConsequence := c.expression(e.Consequence)
+		if kindConsequence != c.expression(e.Alternative) {
+			return KindAny
+		}
+
+		return kindConsequence
+
+	default:
+		return KindAny
+	}
+}
+
+func (c *checker) binary(e *ExpressionBinary) Kind {
+	kindLeft := c.expression(e.LefthandSide)
+	kindRight := c.expression(e.RighthandSide)
+
+	roleLeft := fmt.Sprintf("left side of '%s'", e.Operator)
+	roleRight := fmt.Sprintf("right side of '%s'", e.Operator)
+
+	switch {
+	case isLogicalOperator(e.Operator):
+		c.expect(e.LefthandSide, kindLeft, KindBool, roleLeft)
+		c.expect(e.RighthandSide, kindRight, KindBool, roleRight)
+
+		return KindBool
+
+	case isEqualityOperator(e.Operator):
+		if e.Tolerance != nil {
+			c.expect(e.Tolerance, c.expression(e.Tolerance), KindNumber, fmt.Sprintf("tolerance of '%s'", e.Operator))
+			c.expect(e.LefthandSide, kindLeft, KindNumber, roleLeft)
+			c.expect(e.RighthandSide, kindRight, KindNumber, roleRight)
+
+			return KindBool
+		}
+
+		// equality never fails, but values of different kinds are never
+		// equal: comparing to null is the only legitimate mix.
+		if kindLeft != KindAny && kindRight != KindAny &&
+			kindLeft != KindNull && kindRight != KindNull &&
+			kindLeft != kindRight {
+			c.errorf(
+				e,
+				CodeTypeMismatch,
+				"'%s' compares %s and %s, which are never equal",
+				e.Operator,
+				kindLeft,
+				kindRight,
+			)
+		}
+
+		return KindBool
+
+	case e.Operator == _dslIn || e.Operator == _dslNotIn:
+		c.expect(e.RighthandSide, kindRight, KindList, roleRight)
+
+		return KindBool
+
+	case isStringOperator(e.Operator):
+		c.info.checked[e] = kindLeft == KindString && kindRight == KindString
+
+		c.expect(e.LefthandSide, kindLeft, KindString, roleLeft)
+		c.expect(e.RighthandSide, kindRight, KindString, roleRight)
+
+		return KindBool
+
+	case isComparisonOperator(e.Operator):
+		c.info.checked[e] = c.ordered(e, e.Operator, kindLeft, kindRight) &&
+			kindLeft == kindRight &&
+			kindLeft != KindAny
+
+		return KindBool
+
+	case e.Operator == "+" && (kindLeft == KindString || kindRight == KindString):
+		c.info.checked[e] = kindLeft == KindString && kindRight == KindString
+
+		c.expect(e.LefthandSide, kindLeft, KindString, roleLeft)
+		c.expect(e.RighthandSide, kindRight, KindString, roleRight)
+
+		return KindString
+
+	case isArithmeticOperator(e.Operator):
+		c.info.checked[e] = kindLeft == KindNumber && kindRight == KindNumber
+
+		c.expect(e.LefthandSide, kindLeft, KindNumber, roleLeft)
+		c.expect(e.RighthandSide, kindRight, KindNumber, roleRight)
+
+		return KindNumber
+
+	default:
+		return KindAny
+	}
+}
+
+// ordered checks the operands of an ordering: two numbers or two strings.
+func (c *checker) ordered(e Expression, operator string, kindLeft, kindRight Kind) bool {
+	for _, kind := range []Kind{kindLeft, kindRight} {
+		switch kind {
+		case KindAny, KindNumber, KindString:
+
+		default:
+			c.errorf(
+				e,
+				CodeTypeMismatch,
+				"cannot order %s with '%s' (needs two numbers or two strings)",
+				kind,
+				operator,
+			)
+
+			return false
+		}
+	}
+
+	if kindLeft != KindAny && kindRight != KindAny && kindLeft != kindRight {
+		c.errorf(
+			e,
+			CodeTypeMismatch,
+			"cannot order %s and %s with '%s'",
+			kindLeft,
+			kindRight,
+			operator,
+		)
+
+		return false
+	}
+
+	return true
+}
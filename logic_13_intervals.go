@@ -0,0 +1,606 @@
+package dslalert
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// interval is a range of numbers, each bound open or closed.
+// Unbounded sides use infinities with an open bound.
+type interval struct {
+	lower, upper float64
+
+	isLowerOpen, isUpperOpen bool
+}
+
+func (i interval) isEmpty() bool {
+	return i.lower > i.upper ||
+		(i.lower == i.upper && (i.isLowerOpen || i.isUpperOpen))
+}
+
+func (i interval) isBounded() bool {
+	return !math.IsInf(i.lower, -1) && !math.IsInf(i.upper, 1)
+}
+
+// String prints the interval in math notation, e.g. (5, +Inf) or [10, 10].
+func (i interval) String() string {
+	bracketLower, bracketUpper := "[", "]"
+
+	if i.isLowerOpen {
+		bracketLower = "("
+	}
+
+	if i.isUpperOpen {
+		bracketUpper = ")"
+	}
+
+	return bracketLower +
+		strconv.FormatFloat(i.lower, 'g', -1, 64) +
+		", " +
+		strconv.FormatFloat(i.upper, 'g', -1, 64) +
+		bracketUpper
+}
+
+// intervals is a set of numbers as sorted, disjoint, non adjacent intervals.
+type intervals []interval
+
+var _intervalsAll = intervals{
+	{
+		lower:       math.Inf(-1),
+		upper:       math.Inf(1),
+		isLowerOpen: true,
+		isUpperOpen: true,
+	},
+}
+
+func (s intervals) String() string {
+	result := make([]string, len(s))
+
+	for ix, piece := range s {
+		result[ix] = piece.String()
+	}
+
+	return strings.Join(result, " ∪ ")
+}
+
+// normalized sorts the pieces and merges the ones overlapping or touching.
+func (s intervals) normalized() intervals {
+	pieces := slices.DeleteFunc(slices.Clone(s), interval.isEmpty)
+
+	slices.SortFunc(
+		pieces,
+		func(a, b interval) int {
+			if a.lower == b.lower {
+				// closed lower bound first, it starts earlier
+				return compareBool(a.isLowerOpen, b.isLowerOpen)
+			}
+
+			return cmp.Compare(a.lower, b.lower)
+		},
+	)
+
+	var result intervals
+
+	for _, piece := range pieces {
+		if len(result) == 0 {
+			result = append(result, piece)
+
+			continue
+		}
+
+		last := &result[len(result)-1]
+
+		isTouching := piece.lower < last.upper ||
+			(piece.lower == last.upper && !(last.isUpperOpen && piece.isLowerOpen))
+
+		if !isTouching {
+			result = append(result, piece)
+
+			continue
+		}
+
+		if piece.upper > last.upper ||
+			(piece.upper == last.upper && !piece.isUpperOpen) {
+			last.upper = piece.upper
+			last.isUpperOpen = piece.isUpperOpen
+		}
+	}
+
+	return result
+}
+
+func (s intervals) union(other intervals) intervals {
+	return append(slices.Clone(s), other...).normalized()
+}
+
+func (s intervals) complement() intervals {
+	var result intervals
+
+	from := interval{
+		lower:       math.Inf(-1),
+		isLowerOpen: true,
+	}
+
+	for _, piece := range s.normalized() {
+		from.upper = piece.lower
+		from.isUpperOpen = !piece.isLowerOpen
+
+		result = append(result, from)
+
+		from = interval{
+			lower:       piece.upper,
+			isLowerOpen: !piece.isUpperOpen,
+		}
+	}
+
+	from.upper = math.Inf(1)
+	from.isUpperOpen = true
+
+	return append(result, from).normalized()
+}
+
+func (s intervals) intersect(other intervals) intervals {
+	return s.complement().union(other.complement()).complement()
+}
+
+func (s intervals) subtract(other intervals) intervals {
+	return s.intersect(other.complement())
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+
+	default:
+		return -1
+	}
+}
+
+// valueIntervals computes the numbers of 'value' the condition holds for.
+// Only conditions over 'value' and number constants are analyzed:
+// comparisons, 'within', 'between', 'in' lists and their combinations
+// with and / or / not. The second result is false for any other condition.
+func valueIntervals(condition Expression) (intervals, bool) {
+	switch e := condition.(type) {
+	case *ExpressionLiteral:
+		if e.Value.Kind() != KindBool {
+			return nil, false
+		}
+
+		if e.Value.boolean {
+			return _intervalsAll, true
+		}
+
+		return intervals{}, true
+
+	case *ExpressionUnary:
+		if e.Operator != _dslNot && e.Operator != "!" {
+			return nil, false
+		}
+
+		operand, isAnalyzed := valueIntervals(e.Operand)
+		if !isAnalyzed {
+			return nil, false
+		}
+
+		return operand.complement(), true
+
+	case *ExpressionBetween:
+		lower, isLowerConstant := constantNumber(e.Lower)
+		upper, isUpperConstant := constantNumber(e.Upper)
+
+		if !isValueVariable(e.Operand) || !isLowerConstant || !isUpperConstant {
+			return nil, false
+		}
+
+		result := intervals{{lower: lower, upper: upper}}.normalized()
+		if e.IsNegated {
+			return result.complement(), true
+		}
+
+		return result, true
+
+	case *ExpressionBinary:
+		return binaryIntervals(e)
+
+	default:
+		return nil, false
+	}
+}
+
+func binaryIntervals(e *ExpressionBinary) (intervals, bool) {
+	if isLogicalOperator(e.Operator) {
+		left, isLeftAnalyzed := valueIntervals(e.LefthandSide)
+		right, isRightAnalyzed := valueIntervals(e.RighthandSide)
+
+		if !isLeftAnalyzed || !isRightAnalyzed {
+			return nil, false
+		}
+
+		if e.Operator == _dslAnd {
+			return left.intersect(right), true
+		}
+
+		return left.union(right), true
+	}
+
+	if e.Operator == _dslIn || e.Operator == _dslNotIn {
+		return membershipIntervals(e)
+	}
+
+	if !isComparisonOperator(e.Operator) {
+		return nil, false
+	}
+
+	operator := e.Operator
+
+	threshold, isConstant := constantNumber(e.RighthandSide)
+	isValueLeft := isValueVariable(e.LefthandSide)
+
+	if !isValueLeft {
+		// 5 < value reads value > 5
+		threshold, isConstant = constantNumber(e.LefthandSide)
+		operator = mirrorComparison(operator)
+
+		if !isValueVariable(e.RighthandSide) {
+			return nil, false
+		}
+	}
+
+	if !isConstant {
+		return nil, false
+	}
+
+	var tolerance float64
+
+	if e.Tolerance != nil {
+		var isToleranceConstant bool
+
+		tolerance, isToleranceConstant = constantNumber(e.Tolerance)
+		if !isToleranceConstant || tolerance < 0 {
+			return nil, false
+		}
+	}
+
+	return comparisonIntervals(operator, threshold, tolerance), true
+}
+
+func comparisonIntervals(operator string, threshold, tolerance float64) intervals {
+	var piece interval
+
+	switch operator {
+	case ">", ">=":
+		piece = interval{
+			lower:       threshold,
+			upper:       math.Inf(1),
+			isLowerOpen: operator == ">",
+			isUpperOpen: true,
+		}
+
+	case "<", "<=":
+		piece = interval{
+			lower:       math.Inf(-1),
+			upper:       threshold,
+			isLowerOpen: true,
+			isUpperOpen: operator == "<",
+		}
+
+	default:
+		piece = interval{
+			lower: threshold - tolerance,
+			upper: threshold + tolerance,
+		}
+	}
+
+	result := intervals{piece}.normalized()
+	if operator == "!=" {
+		return result.complement()
+	}
+
+	return result
+}
+
+func membershipIntervals(e *ExpressionBinary) (intervals, bool) {
+	list, isList := e.RighthandSide.(*ExpressionList)
+	if !isValueVariable(e.LefthandSide) || !isList {
+		return nil, false
+	}
+
+	var result intervals
+
+	for _, element := range list.Elements {
+		number, isConstant := constantNumber(element)
+		if !isConstant {
+			return nil, false
+		}
+
+		result = append(result, interval{lower: number, upper: number})
+	}
+
+	result = result.normalized()
+	if e.Operator == _dslNotIn {
+		return result.complement(), true
+	}
+
+	return result, true
+}
+
+func mirrorComparison(operator string) string {
+	switch operator {
+	case ">":
+		return "<"
+	case ">=":
+		return "<="
+	case "<":
+		return ">"
+	case "<=":
+		return ">="
+
+	default:
+		return operator // == and != are symmetric
+	}
+}
+
+func isValueVariable(expression Expression) bool {
+	variable, isVariable := expression.(*ExpressionVariable)
+
+	return isVariable && variable.Name == _dslValue
+}
+
+// constantNumber returns the number of a literal, possibly signed.
+func constantNumber(expression Expression) (float64, bool) {
+	switch e := expression.(type) {
+	case *ExpressionLiteral:
+		return e.Value.number,
+			e.Value.Kind() == KindNumber
+
+	case *ExpressionUnary:
+		number, isConstant := constantNumber(e.Operand)
+
+		switch e.Operator {
+		case "-":
+			return -number, isConstant
+		case "+":
+			return number, isConstant
+
+		default:
+			return 0, false
+		}
+
+	default:
+		return 0, false
+	}
+}
+
+// levelIntervals is what a rule matches, then what it is left with once
+// the rules of higher levels, evaluated first, took their values.
+type levelIntervals struct {
+	rule *Rule
+
+	matched   intervals
+	effective intervals
+}
+
+// lintIntervals analyzes the rules of a monitor over the numbers of 'value'.
+// EvaluateCriteria tries rules by level descending and stops at the first
+// match, so a rule whose values all match higher levels never fires.
+// When every rule is analyzed, it also reports values between the levels
+// that no rule matches, and levels whose values do not keep moving in the
+// same direction as the level rises.
+func lintIntervals(monitor *Monitor) Diagnostics {
+	rules := rulesByLevel(monitor)
+
+	var (
+		result   Diagnostics
+		analyzed []levelIntervals
+		covered  intervals
+	)
+
+	isComplete := true
+
+	for _, rule := range rules {
+		matched, isAnalyzed := valueIntervals(rule.Condition)
+		if !isAnalyzed {
+			isComplete = false
+
+			continue
+		}
+
+		effective := matched.subtract(covered)
+
+		switch {
+		case len(matched) == 0:
+			result = append(
+				result,
+				findingAt(
+					rule.Span,
+					CodeUnreachableLevel,
+					"level %d never fires: its condition matches no value",
+					rule.Level,
+				),
+			)
+
+		case len(effective) == 0:
+			result = append(
+				result,
+				findingAt(
+					rule.Span,
+					CodeUnreachableLevel,
+					"level %d never fires: every value it matches, %s, already matches %s",
+					rule.Level,
+					matched,
+					levelsOverlapping(analyzed, matched),
+				),
+			)
+
+		default:
+			analyzed = append(
+				analyzed,
+
+				levelIntervals{
+					rule:      rule,
+					matched:   matched,
+					effective: effective,
+				},
+			)
+		}
+
+		covered = covered.union(matched)
+	}
+
+	if !isComplete {
+		return result
+	}
+
+	result = append(result, lintGaps(monitor, analyzed, covered)...)
+
+	return append(result, lintMonotonic(analyzed)...)
+}
+
+// lintGaps reports the values no level matches, lying between values
+// of two different levels. Values around one level only, like the normal
+// range of 'value < 0 or value > 100', are left alone.
+func lintGaps(monitor *Monitor, analyzed []levelIntervals, covered intervals) Diagnostics {
+	var result Diagnostics
+
+	for _, gap := range covered.complement() {
+		if !gap.isBounded() {
+			continue
+		}
+
+		levelBelow := levelEndingAt(analyzed, gap.lower)
+		levelAbove := levelStartingAt(analyzed, gap.upper)
+
+		if levelBelow == nil || levelAbove == nil || levelBelow == levelAbove {
+			continue
+		}
+
+		result = append(
+			result,
+			findingAt(
+				monitor.Span,
+				CodeLevelGap,
+				"no level of monitor '%s' matches values in %s, between level %d and level %d",
+				monitor.ColumnName,
+				gap,
+				levelBelow.Level,
+				levelAbove.Level,
+			),
+		)
+	}
+
+	return result
+}
+
+// lintMonotonic reports a level whose values lie on the other side of the
+// level below it than the previous levels did, e.g. level 3 above level 2
+// but level 2 below level 1. Only levels matching one interval are ranked.
+func lintMonotonic(analyzed []levelIntervals) Diagnostics {
+	var (
+		result    Diagnostics
+		direction int
+	)
+
+	for ix := 1; ix < len(analyzed); ix++ {
+		higher, lower := analyzed[ix-1], analyzed[ix]
+
+		if len(higher.effective) != 1 || len(lower.effective) != 1 {
+			direction = 0
+
+			continue
+		}
+
+		// effective intervals are disjoint, one lies entirely below the other
+		directionPair := 1
+		if higher.effective[0].upper <= lower.effective[0].lower {
+			directionPair = -1
+		}
+
+		if direction != 0 && directionPair != direction {
+			result = append(
+				result,
+				findingAt(
+					lower.rule.Span,
+					CodeNonMonotonic,
+					"thresholds are not monotonic: level %d lies %s level %d, but level %d lies %s level %d, which matches %s",
+					analyzed[ix-2].rule.Level,
+					describeSide(direction),
+					higher.rule.Level,
+					higher.rule.Level,
+					describeSide(directionPair),
+					lower.rule.Level,
+					lower.effective,
+				),
+			)
+		}
+
+		direction = directionPair
+	}
+
+	return result
+}
+
+func describeSide(direction int) string {
+	if direction > 0 {
+		return "above"
+	}
+
+	return "below"
+}
+
+func levelsOverlapping(analyzed []levelIntervals, matched intervals) string {
+	var levels []string
+
+	for _, level := range analyzed {
+		if len(level.effective.intersect(matched)) > 0 {
+			levels = append(levels, strconv.Itoa(level.rule.Level))
+		}
+	}
+
+	if len(levels) == 1 {
+		return "level " + levels[0]
+	}
+
+	return "levels " + strings.Join(levels, ", ")
+}
+
+func levelEndingAt(analyzed []levelIntervals, bound float64) *Rule {
+	for _, level := range analyzed {
+		for _, piece := range level.effective {
+			if piece.upper == bound {
+				return level.rule
+			}
+		}
+	}
+
+	return nil
+}
+
+func levelStartingAt(analyzed []levelIntervals, bound float64) *Rule {
+	for _, level := range analyzed {
+		for _, piece := range level.effective {
+			if piece.lower == bound {
+				return level.rule
+			}
+		}
+	}
+
+	return nil
+}
+
+// findingAt builds a finding of the linter, which sets its severity.
+func findingAt(span Span, code Code, format string, args ...any) Diagnostic {
+	return Diagnostic{
+		Code:    code,
+		Start:   span.Start,
+		End:     span.End,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,671 @@
+package dslalert
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// _directiveIgnore starts a comment suppressing findings on its node,
+// e.g. "// dsl:ignore DSL022 missing-doc" above or after a monitor.
+const _directiveIgnore = "dsl:ignore"
+
+// monitorsMaximumDefault is the number of monitors a criteria may hold
+// before too-many-monitors reports it.
+const monitorsMaximumDefault = 10
+
+// LintCheck describes a check of the linter.
+// Checks are configured and suppressed by Name or by Code.
+type LintCheck struct {
+	Name        string
+	Code        Code
+	Severity    Severity // default severity of the findings
+	IsDefault   bool     // part of the default set
+	Description string
+}
+
+// String prints the check as listed by documentation and tools.
+func (c LintCheck) String() string {
+	return fmt.Sprintf(
+		"%s %s (%s): %s",
+
+		c.Code,
+		c.Name,
+		c.Severity,
+		c.Description,
+	)
+}
+
+type lintCheck struct {
+	LintCheck
+
+	run func(l *Linter, configuration *AlertConfiguration) Diagnostics
+
+	// isIntervals reports the findings of the interval analysis with the
+	// code of the check instead of running on its own.
+	isIntervals bool
+}
+
+var _lintChecks = []lintCheck{
+	{
+		LintCheck: LintCheck{
+			Name:        "unreachable-level",
+			Code:        CodeUnreachableLevel,
+			Severity:    SeverityWarning,
+			IsDefault:   true,
+			Description: "level never fires, higher levels match all its values",
+		},
+		isIntervals: true,
+	},
+	{
+		LintCheck: LintCheck{
+			Name:        "level-gap",
+			Code:        CodeLevelGap,
+			Severity:    SeverityWarning,
+			IsDefault:   true,
+			Description: "values between two levels that no level matches",
+		},
+		isIntervals: true,
+	},
+	{
+		LintCheck: LintCheck{
+			Name:        "non-monotonic",
+			Code:        CodeNonMonotonic,
+			Severity:    SeverityWarning,
+			IsDefault:   true,
+			Description: "levels not moving in one direction as thresholds rise",
+		},
+		isIntervals: true,
+	},
+	{
+		LintCheck: LintCheck{
+			Name:        "repeated-number",
+			Code:        CodeRepeatedNumber,
+			Severity:    SeverityInfo,
+			IsDefault:   true,
+			Description: "number other than 0 and 1 repeated across monitors",
+		},
+		run: (*Linter).lintRepeatedNumbers,
+	},
+	{
+		LintCheck: LintCheck{
+			Name:        "missing-doc",
+			Code:        CodeMissingDoc,
+			Severity:    SeverityInfo,
+			Description: "criteria or monitor without a doc comment",
+		},
+		run: (*Linter).lintMissingDoc,
+	},
+	{
+		LintCheck: LintCheck{
+			Name:        "level-start",
+			Code:        CodeLevelStart,
+			Severity:    SeverityWarning,
+			IsDefault:   true,
+			Description: "levels of a monitor not starting at 1",
+		},
+		run: (*Linter).lintLevelStart,
+	},
+	{
+		LintCheck: LintCheck{
+			Name:        "too-many-monitors",
+			Code:        CodeTooManyMonitors,
+			Severity:    SeverityWarning,
+			IsDefault:   true,
+			Description: "criteria with more monitors than ParamsLint.MonitorsMaximum",
+		},
+		run: (*Linter).lintTooManyMonitors,
+	},
+	{
+		LintCheck: LintCheck{
+			Name:        "float-equality",
+			Code:        CodeFloatEquality,
+			Severity:    SeverityWarning,
+			IsDefault:   true,
+			Description: "exact equality against a fractional number, without 'within'",
+		},
+		run: (*Linter).lintFloatEquality,
+	},
+}
+
+// LintChecks lists the checks of the linter, in the order they run.
+func LintChecks() []LintCheck {
+	result := make([]LintCheck, len(_lintChecks))
+
+	for ix, check := range _lintChecks {
+		result[ix] = check.LintCheck
+	}
+
+	return result
+}
+
+// ParamsLint configures NewLinter.
+// Checks are named by Name or by Code, e.g. "missing-doc" or "DSL023".
+type ParamsLint struct {
+	Enable  []string // checks to run on top of the default set
+	Disable []string // checks of the default set not to run
+
+	Severities map[string]Severity // severity of the findings per check, named once
+
+	MonitorsMaximum int // monitors per criteria, defaults to 10
+}
+
+// Linter runs style and safety checks over valid configurations.
+// Its findings are not errors of the configuration: they carry the
+// severity of their check, warning or info unless configured otherwise.
+type Linter struct {
+	checks []lintCheck
+
+	monitorsMaximum int
+}
+
+func NewLinter(params *ParamsLint) (*Linter, error) {
+	for _, name := range slices.Concat(
+		params.Enable,
+		params.Disable,
+		slices.Collect(maps.Keys(params.Severities)),
+	) {
+		if _, exists := lookupLintCheck(name); !exists {
+			return nil,
+				goerrors.ErrValidation{
+					Caller: "NewLinter",
+					Issue: goerrors.ErrInvalidInput{
+						InputName:  "name",
+						InputValue: name,
+						Issue:      errors.New("unknown lint check"),
+					},
+				}
+		}
+	}
+
+	// a check named by both name and code would get either severity.
+	namesSeverity := make(map[Code]string, len(params.Severities))
+
+	for _, name := range slices.Sorted(maps.Keys(params.Severities)) {
+		check, _ := lookupLintCheck(name)
+
+		if nameOther, exists := namesSeverity[check.Code]; exists {
+			return nil,
+				goerrors.ErrValidation{
+					Caller: "NewLinter",
+					Issue: goerrors.ErrInvalidInput{
+						InputName:  "severities",
+						InputValue: name,
+						Issue: fmt.Errorf(
+							"names the check already named '%s'",
+							nameOther,
+						),
+					},
+				}
+		}
+
+		namesSeverity[check.Code] = name
+	}
+
+	result := Linter{
+		monitorsMaximum: params.MonitorsMaximum,
+	}
+
+	if result.monitorsMaximum <= 0 {
+		result.monitorsMaximum = monitorsMaximumDefault
+	}
+
+	for _, check := range _lintChecks {
+		isEnabled := (check.IsDefault && !check.isNamedIn(params.Disable)) ||
+			check.isNamedIn(params.Enable)
+
+		if !isEnabled {
+			continue
+		}
+
+		for name, severity := range params.Severities {
+			if check.isNamed(name) {
+				check.Severity = severity
+			}
+		}
+
+		result.checks = append(result.checks, check)
+	}
+
+	return &result, nil
+}
+
+func lookupLintCheck(name string) (lintCheck, bool) {
+	for _, check := range _lintChecks {
+		if check.isNamed(name) {
+			return check, true
+		}
+	}
+
+	return lintCheck{}, false
+}
+
+func (c lintCheck) isNamed(name string) bool {
+	return name == c.Name || name == string(c.Code)
+}
+
+func (c lintCheck) isNamedIn(names []string) bool {
+	return slices.ContainsFunc(names, c.isNamed)
+}
+
+// Lint runs the default checks, see LintChecks.
+func Lint(configuration *AlertConfiguration) Diagnostics {
+	linter, _ := NewLinter(&ParamsLint{})
+
+	return linter.Lint(configuration)
+}
+
+// Lint reports the findings of the enabled checks, in source order.
+// Findings on a criteria, monitor or rule whose doc, opening brace or
+// trailing comment holds "dsl:ignore" followed by the name or code of
+// their check are suppressed, as are the findings on the nodes it contains.
+// The configuration is expected valid, see Validate. Error nodes of
+// tolerant parsing are skipped.
+func (l *Linter) Lint(configuration *AlertConfiguration) Diagnostics {
+	suppressions := collectSuppressions(configuration)
+
+	// the interval analysis is run once for the checks sharing it.
+	intervals := sync.OnceValue(
+		func() Diagnostics {
+			return lintIntervalsAll(configuration)
+		},
+	)
+
+	var result Diagnostics
+
+	for _, check := range l.checks {
+		var findings Diagnostics
+
+		if check.isIntervals {
+			findings = findingsWithCode(intervals(), check.Code)
+		} else {
+			findings = check.run(l, configuration)
+		}
+
+		for _, finding := range findings {
+			if suppressions.isSuppressed(finding, check) {
+				continue
+			}
+
+			finding.Severity = check.Severity
+
+			result = append(result, finding)
+		}
+	}
+
+	slices.SortStableFunc(
+		result,
+		func(a, b Diagnostic) int {
+			return a.Start.Offset - b.Start.Offset
+		},
+	)
+
+	return result
+}
+
+// suppression is a dsl:ignore directive and the node it applies to.
+type suppression struct {
+	span  Span
+	names []string
+}
+
+type suppressions []suppression
+
+func collectSuppressions(configuration *AlertConfiguration) suppressions {
+	var result suppressions
+
+	add := func(span Span, comments ...string) {
+		if names := ignoredNames(comments...); len(names) > 0 {
+			result = append(
+				result,
+
+				suppression{
+					span:  span,
+					names: names,
+				},
+			)
+		}
+	}
+
+	Inspect(
+		configuration,
+		func(node Node) bool {
+			switch n := node.(type) {
+			case *Criteria:
+				add(n.Span, n.Doc, n.CommentOpening, n.Comment)
+
+			case *Monitor:
+				add(n.Span, n.Doc, n.CommentOpening, n.Comment)
+
+			case *Rule:
+				add(n.Span, n.Doc, n.Comment)
+
+				return false // conditions hold no comments
+			}
+
+			return true
+		},
+	)
+
+	return result
+}
+
+// ignoredNames reads the checks named by dsl:ignore lines of the comments,
+// separated by blanks or commas.
+func ignoredNames(comments ...string) []string {
+	var result []string
+
+	for _, comment := range comments {
+		for line := range strings.SplitSeq(comment, "\n") {
+			names, isDirective := strings.CutPrefix(strings.TrimSpace(line), _directiveIgnore)
+			if !isDirective {
+				continue
+			}
+
+			result = append(
+				result,
+
+				strings.FieldsFunc(
+					names,
+					func(ch rune) bool {
+						return ch == ',' || ch == ' ' || ch == '\t'
+					},
+				)...,
+			)
+		}
+	}
+
+	return result
+}
+
+func (s suppressions) isSuppressed(finding Diagnostic, check lintCheck) bool {
+	for _, suppression := range s {
+		isInside := finding.Start.Offset >= suppression.span.Start.Offset &&
+			finding.Start.Offset < suppression.span.End.Offset
+
+		if isInside && check.isNamedIn(suppression.names) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// eachMonitor calls f for the monitors of the configuration,
+// skipping error nodes.
+func eachMonitor(configuration *AlertConfiguration, f func(criteria *Criteria, monitor *Monitor)) {
+	for _, criteria := range configuration.Criterias {
+		if criteria.IsError {
+			continue
+		}
+
+		for _, monitor := range criteria.Monitors {
+			if !monitor.IsError {
+				f(criteria, monitor)
+			}
+		}
+	}
+}
+
+// rulesOf returns the rules of the monitor, skipping error nodes.
+func rulesOf(monitor *Monitor) []*Rule {
+	return slices.DeleteFunc(
+		slices.Clone(monitor.Rules),
+		func(rule *Rule) bool {
+			return rule.IsError
+		},
+	)
+}
+
+// lintIntervalsAll runs the interval analysis on every monitor.
+func lintIntervalsAll(configuration *AlertConfiguration) Diagnostics {
+	var result Diagnostics
+
+	eachMonitor(
+		configuration,
+		func(_ *Criteria, monitor *Monitor) {
+			result = append(result, lintIntervals(monitor)...)
+		},
+	)
+
+	return result
+}
+
+// findingsWithCode keeps the findings of one code, so each check of the
+// interval analysis is configured on its own.
+func findingsWithCode(findings Diagnostics, code Code) Diagnostics {
+	var result Diagnostics
+
+	for _, finding := range findings {
+		if finding.Code == code {
+			result = append(result, finding)
+		}
+	}
+
+	return result
+}
+
+func (l *Linter) lintRepeatedNumbers(configuration *AlertConfiguration) Diagnostics {
+	type usage struct {
+		criteria *Criteria
+		monitor  *Monitor
+	}
+
+	var result Diagnostics
+
+	usagesFirst := make(map[float64]usage)
+
+	eachMonitor(
+		configuration,
+		func(criteria *Criteria, monitor *Monitor) {
+			reported := make(map[float64]bool)
+
+			for _, rule := range rulesOf(monitor) {
+				Inspect(
+					rule.Condition,
+					func(node Node) bool {
+						literal, isLiteral := node.(*ExpressionLiteral)
+						if !isLiteral || literal.Value.Kind() != KindNumber {
+							return true
+						}
+
+						number := math.Abs(literal.Value.number)
+						if number == 0 || number == 1 {
+							return true
+						}
+
+						first, isUsed := usagesFirst[number]
+						if !isUsed {
+							usagesFirst[number] = usage{
+								criteria: criteria,
+								monitor:  monitor,
+							}
+
+							return true
+						}
+
+						if first.monitor == monitor || reported[number] {
+							return true
+						}
+
+						reported[number] = true
+
+						result = append(
+							result,
+							findingAt(
+								literal.Span,
+								CodeRepeatedNumber,
+								"number %s is repeated from monitor '%s' of criteria '%s', changing one of them misses the other",
+								literal.Raw,
+								first.monitor.ColumnName,
+								first.criteria.Name,
+							),
+						)
+
+						return true
+					},
+				)
+			}
+		},
+	)
+
+	return result
+}
+
+func (l *Linter) lintMissingDoc(configuration *AlertConfiguration) Diagnostics {
+	var result Diagnostics
+
+	for _, criteria := range configuration.Criterias {
+		if !criteria.IsError && criteria.Doc == "" {
+			result = append(
+				result,
+				findingAt(
+					criteria.Span,
+					CodeMissingDoc,
+					"criteria '%s' has no doc comment",
+					criteria.Name,
+				),
+			)
+		}
+	}
+
+	eachMonitor(
+		configuration,
+		func(criteria *Criteria, monitor *Monitor) {
+			if monitor.Doc != "" {
+				return
+			}
+
+			result = append(
+				result,
+				findingAt(
+					monitor.Span,
+					CodeMissingDoc,
+					"monitor '%s' of criteria '%s' has no doc comment",
+					monitor.ColumnName,
+					criteria.Name,
+				),
+			)
+		},
+	)
+
+	return result
+}
+
+func (l *Linter) lintLevelStart(configuration *AlertConfiguration) Diagnostics {
+	var result Diagnostics
+
+	eachMonitor(
+		configuration,
+		func(_ *Criteria, monitor *Monitor) {
+			rules := rulesOf(monitor)
+			if len(rules) == 0 {
+				return
+			}
+
+			lowest := slices.MinFunc(
+				rules,
+				func(a, b *Rule) int {
+					return a.Level - b.Level
+				},
+			)
+
+			if lowest.Level == 1 {
+				return
+			}
+
+			result = append(
+				result,
+				findingAt(
+					lowest.Span,
+					CodeLevelStart,
+					"levels of monitor '%s' start at %d, not 1",
+					monitor.ColumnName,
+					lowest.Level,
+				),
+			)
+		},
+	)
+
+	return result
+}
+
+func (l *Linter) lintTooManyMonitors(configuration *AlertConfiguration) Diagnostics {
+	var result Diagnostics
+
+	for _, criteria := range configuration.Criterias {
+		if criteria.IsError {
+			continue
+		}
+
+		count := len(criteria.Monitors)
+		if count <= l.monitorsMaximum {
+			continue
+		}
+
+		result = append(
+			result,
+			findingAt(
+				criteria.Span,
+				CodeTooManyMonitors,
+				"criteria '%s' has %d monitors, more than %d",
+				criteria.Name,
+				count,
+				l.monitorsMaximum,
+			),
+		)
+	}
+
+	return result
+}
+
+func (l *Linter) lintFloatEquality(configuration *AlertConfiguration) Diagnostics {
+	var result Diagnostics
+
+	eachMonitor(
+		configuration,
+		func(_ *Criteria, monitor *Monitor) {
+			for _, rule := range rulesOf(monitor) {
+				Inspect(
+					rule.Condition,
+					func(node Node) bool {
+						binary, isBinary := node.(*ExpressionBinary)
+						if !isBinary || !isEqualityOperator(binary.Operator) || binary.Tolerance != nil {
+							return true
+						}
+
+						for _, operand := range []Expression{binary.LefthandSide, binary.RighthandSide} {
+							number, isConstant := constantNumber(operand)
+
+							if isConstant && number != math.Trunc(number) {
+								result = append(
+									result,
+									findingAt(
+										binary.Span,
+										CodeFloatEquality,
+										"'%s' compares exactly with the fraction %s, add '%s' and a tolerance",
+										binary.Operator,
+										FormatExpression(operand),
+										_dslWithin,
+									),
+								)
+
+								break
+							}
+						}
+
+						return true
+					},
+				)
+			}
+		},
+	)
+
+	return result
+}
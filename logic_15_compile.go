@@ -0,0 +1,887 @@
+package dslalert
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// evaluator is an expression compiled to a closure. Operators are resolved
+// and constant lists built once, so evaluating a row walks no tree and
+// allocates nothing, except for function calls and lists of variables.
+// It gives the results and errors of evaluateExpression.
+type evaluator func(row *rowContext) (Value, error)
+
+// evaluatorCondition is a rule condition compiled once per configuration.
+type evaluatorCondition func(row *rowContext) (bool, error)
+
+// ruleCompiled is a rule with its condition compiled.
+type ruleCompiled struct {
+	level int
+	doc   string
+
+	condition evaluatorCondition
+}
+
+// monitorCompiled holds the rules of a monitor by level descending,
+// the order they are tried in.
+type monitorCompiled struct {
+	columnName string
+	doc        string
+
+	rules []ruleCompiled
+}
+
+// criteriaCompiled is what evaluating a criteria needs, without the tree.
+type criteriaCompiled struct {
+	name string
+	doc  string
+
+	monitors []monitorCompiled
+}
+
+// compileCriteria compiles the optimized rules of the criteria, skipping
+// error nodes. The criteria is left unchanged.
+func compileCriteria(criteria *Criteria) *criteriaCompiled {
+	return newCriteriaCompiled(
+		criteria,
+		func(rule *Rule) evaluatorCondition {
+			condition := optimizeCondition(rule.Condition)
+
+			return compileCondition(condition, checkCondition(condition))
+		},
+	)
+}
+
+// newCriteriaCompiled lays out the criteria for evaluation, the conditions
+// compiled by compile.
+func newCriteriaCompiled(criteria *Criteria, compile func(*Rule) evaluatorCondition) *criteriaCompiled {
+	result := criteriaCompiled{
+		name:     criteria.Name,
+		doc:      criteria.Doc,
+		monitors: make([]monitorCompiled, 0, len(criteria.Monitors)),
+	}
+
+	for _, monitor := range criteria.Monitors {
+		if monitor.IsError {
+			continue
+		}
+
+		compiled := monitorCompiled{
+			columnName: monitor.ColumnName,
+			doc:        monitor.Doc,
+		}
+
+		for _, rule := range rulesByLevel(monitor) {
+			compiled.rules = append(
+				compiled.rules,
+
+				ruleCompiled{
+					level:     rule.Level,
+					doc:       rule.Doc,
+					condition: compile(rule),
+				},
+			)
+		}
+
+		result.monitors = append(result.monitors, compiled)
+	}
+
+	return &result
+}
+
+// rulesByLevel returns the rules of the monitor by level descending,
+// skipping error nodes.
+func rulesByLevel(monitor *Monitor) []*Rule {
+	result := rulesOf(monitor)
+
+	slices.SortStableFunc(
+		result,
+		func(a, b *Rule) int {
+			return cmp.Compare(b.Level, a.Level)
+		},
+	)
+
+	return result
+}
+
+// compiler compiles the expressions of one condition, the operations
+// whose operand kinds Check proved skipping their runtime kind checks.
+type compiler struct {
+	info *TypeInfo
+}
+
+func compileCondition(condition Expression, info *TypeInfo) evaluatorCondition {
+	c := &compiler{
+		info: info,
+	}
+
+	if result, isCondition := c.compileBool(condition); isCondition {
+		return result
+	}
+
+	evaluate := c.compileExpression(condition)
+
+	return func(row *rowContext) (bool, error) {
+		result, errEvaluate := evaluate(row)
+		if errEvaluate != nil {
+			return false, errEvaluate
+		}
+
+		return conditionResult(result)
+	}
+}
+
+func compileConstant(value Value) evaluator {
+	return func(*rowContext) (Value, error) {
+		return value, nil
+	}
+}
+
+func compileError(err error) evaluator {
+	return func(*rowContext) (Value, error) {
+		return Value{}, err
+	}
+}
+
+func (c *compiler) compileExpression(expr Expression) evaluator {
+	switch e := expr.(type) {
+	case *ExpressionLiteral:
+		return compileConstant(e.Value)
+
+	case *ExpressionVariable:
+		if e.Name == _dslValue {
+			return func(row *rowContext) (Value, error) {
+				return row.value, nil
+			}
+		}
+
+		name := e.Name
+
+		return func(row *rowContext) (Value, error) {
+			return row.column(name)
+		}
+
+	case *ExpressionUnary:
+		return c.compileUnary(e)
+
+	case *ExpressionCall:
+		return c.compileCall(e)
+
+	case *ExpressionList:
+		return c.compileList(e)
+
+	case *ExpressionBetween:
+		condition := c.compileBoolBetween(e)
+
+		return func(row *rowContext) (Value, error) {
+			result, errEvaluate := condition(row)
+			if errEvaluate != nil {
+				return Value{}, errEvaluate
+			}
+
+			return boolValue(result), nil
+		}
+
+	case *ExpressionConditional:
+		return c.compileConditional(e)
+
+	case *ExpressionBinary:
+		return c.compileBinary(e)
+
+	default:
+		return compileError(
+			fmt.Errorf(
+				"unsupported expression type %T",
+				expr,
+			),
+		)
+	}
+}
+
+func (c *compiler) compileUnary(e *ExpressionUnary) evaluator {
+	operand := c.compileExpression(e.Operand)
+	operator := e.Operator
+
+	evaluateOperand := func(row *rowContext) (Value, error) {
+		valueOperand, errEvaluateOperand := operand(row)
+		if errEvaluateOperand != nil {
+			return Value{},
+				fmt.Errorf(
+					"failed to evaluate operand of '%s': %w",
+					operator,
+					errEvaluateOperand,
+				)
+		}
+
+		return valueOperand, nil
+	}
+
+	if !c.info.isChecked(e) {
+		return func(row *rowContext) (Value, error) {
+			valueOperand, errOperand := evaluateOperand(row)
+			if errOperand != nil {
+				return Value{}, errOperand
+			}
+
+			return unaryResult(operator, valueOperand)
+		}
+	}
+
+	switch operator {
+	case _dslNot, "!":
+		return func(row *rowContext) (Value, error) {
+			valueOperand, errOperand := evaluateOperand(row)
+			if errOperand != nil {
+				return Value{}, errOperand
+			}
+
+			return boolValue(!valueOperand.boolean), nil
+		}
+
+	case "-":
+		return func(row *rowContext) (Value, error) {
+			valueOperand, errOperand := evaluateOperand(row)
+			if errOperand != nil {
+				return Value{}, errOperand
+			}
+
+			return numberValue(-valueOperand.number), nil
+		}
+
+	default:
+		return evaluateOperand
+	}
+}
+
+func (c *compiler) compileCall(e *ExpressionCall) evaluator {
+	// calls built outside Parse, e.g. by Transform, are not resolved.
+	if e.function == nil {
+		return compileError(
+			fmt.Errorf(
+				"function '%s' is not resolved",
+				e.Name,
+			),
+		)
+	}
+
+	arguments := make([]evaluator, len(e.Arguments))
+
+	for ix, argument := range e.Arguments {
+		arguments[ix] = c.compileExpression(argument)
+	}
+
+	evaluateArgument := func(ix int, row *rowContext) (Value, error) {
+		valueArgument, errEvaluateArgument := arguments[ix](row)
+		if errEvaluateArgument != nil {
+			return Value{},
+				fmt.Errorf(
+					"failed to evaluate argument %d of '%s': %w",
+					ix+1,
+					e.Name,
+					errEvaluateArgument,
+				)
+		}
+
+		return valueArgument, nil
+	}
+
+	if e.function.implementationNumeric == nil {
+		return func(row *rowContext) (Value, error) {
+			valuesArguments := make([]any, len(arguments))
+
+			for ix := range arguments {
+				valueArgument, errArgument := evaluateArgument(ix, row)
+				if errArgument != nil {
+					return Value{}, errArgument
+				}
+
+				if errArgument := hostArgument(e, ix, valueArgument); errArgument != nil {
+					return Value{}, errArgument
+				}
+
+				valuesArguments[ix] = valueArgument.any()
+			}
+
+			return callHost(e, valuesArguments)
+		}
+	}
+
+	return func(row *rowContext) (Value, error) {
+		numbers := make([]float64, len(arguments))
+
+		for ix := range arguments {
+			valueArgument, errArgument := evaluateArgument(ix, row)
+			if errArgument != nil {
+				return Value{}, errArgument
+			}
+
+			number, errNumber := numericArgument(e, ix, valueArgument)
+			if errNumber != nil {
+				return Value{}, errNumber
+			}
+
+			numbers[ix] = number
+		}
+
+		return callNumeric(e, numbers)
+	}
+}
+
+// compileList builds a list of literals once, only lists holding
+// variables or operations are built per row.
+func (c *compiler) compileList(e *ExpressionList) evaluator {
+	constants := make([]Value, len(e.Elements))
+	elements := make([]evaluator, len(e.Elements))
+
+	isConstant := true
+
+	for ix, element := range e.Elements {
+		if literal, isLiteral := element.(*ExpressionLiteral); isLiteral {
+			constants[ix] = literal.Value
+		} else {
+			isConstant = false
+		}
+
+		elements[ix] = c.compileExpression(element)
+	}
+
+	if isConstant {
+		return compileConstant(listValue(constants))
+	}
+
+	return func(row *rowContext) (Value, error) {
+		values := make([]Value, len(elements))
+
+		for ix, element := range elements {
+			valueElement, errEvaluateElement := element(row)
+			if errEvaluateElement != nil {
+				return Value{},
+					fmt.Errorf(
+						"failed to evaluate list element %d: %w",
+						ix+1,
+						errEvaluateElement,
+					)
+			}
+
+			values[ix] = valueElement
+		}
+
+		return listValue(values), nil
+	}
+}
+
+func (c *compiler) compileBetween(e *ExpressionBetween) evaluator {
+	parts := [3]evaluator{
+		c.compileExpression(e.Operand),
+		c.compileExpression(e.Lower),
+		c.compileExpression(e.Upper),
+	}
+
+	return func(row *rowContext) (Value, error) {
+		var values [3]Value
+
+		for ix, part := range parts {
+			valuePart, errEvaluatePart := part(row)
+			if errEvaluatePart != nil {
+				return Value{},
+					fmt.Errorf(
+						"failed to evaluate '%s': %w",
+						_dslBetween,
+						errEvaluatePart,
+					)
+			}
+
+			values[ix] = valuePart
+		}
+
+		return betweenResult(e.IsNegated, values)
+	}
+}
+
+func (c *compiler) compileConditional(e *ExpressionConditional) evaluator {
+	condition := c.compileExpression(e.Condition)
+	consequence := c.compileExpression(e.Consequence)
+	alternative := c.compileExpression(e.Alternative)
+
+	return func(row *rowContext) (Value, error) {
+		valueCondition, errEvaluateCondition := condition(row)
+		if errEvaluateCondition != nil {
+			return Value{},
+				fmt.Errorf(
+					"failed to evaluate condition of '%s': %w",
+					_dslIf,
+					errEvaluateCondition,
+				)
+		}
+
+		isConsequence, errCondition := conditionalChoice(valueCondition)
+		if errCondition != nil {
+			return Value{}, errCondition
+		}
+
+		if isConsequence {
+			return consequence(row)
+		}
+
+		return alternative(row)
+	}
+}
+
+// compileSides compiles the operands of a binary operation, wrapping
+// their errors like evaluateExpression does.
+func (c *compiler) compileSides(e *ExpressionBinary) (evaluator, evaluator) {
+	left := c.compileExpression(e.LefthandSide)
+	right := c.compileExpression(e.RighthandSide)
+	operator := e.Operator
+
+	evaluateLeft := func(row *rowContext) (Value, error) {
+		valueLeft, errEvaluateLeft := left(row)
+		if errEvaluateLeft != nil {
+			return Value{},
+				fmt.Errorf(
+					"failed to evaluate left side of '%s': %w",
+					operator,
+					errEvaluateLeft,
+				)
+		}
+
+		return valueLeft, nil
+	}
+
+	evaluateRight := func(row *rowContext) (Value, error) {
+		valueRight, errEvaluateRight := right(row)
+		if errEvaluateRight != nil {
+			return Value{},
+				fmt.Errorf(
+					"failed to evaluate right side of '%s': %w",
+					operator,
+					errEvaluateRight,
+				)
+		}
+
+		return valueRight, nil
+	}
+
+	return evaluateLeft, evaluateRight
+}
+
+// compileBinary resolves the operator once. Operations producing a bool
+// are compiled by compileBool, arithmetic on two numbers skips the
+// generic helpers.
+func (c *compiler) compileBinary(e *ExpressionBinary) evaluator {
+	if condition, isCondition := c.compileBool(e); isCondition {
+		return func(row *rowContext) (Value, error) {
+			result, errEvaluate := condition(row)
+			if errEvaluate != nil {
+				return Value{}, errEvaluate
+			}
+
+			return boolValue(result), nil
+		}
+	}
+
+	evaluateLeft, evaluateRight := c.compileSides(e)
+	operator := e.Operator
+
+	if isLogicalOperator(operator) {
+		return func(row *rowContext) (Value, error) {
+			valueLeft, errLeft := evaluateLeft(row)
+			if errLeft != nil {
+				return Value{}, errLeft
+			}
+
+			result, isDecided, errDecide := logicalLeft(operator, valueLeft)
+			if isDecided || errDecide != nil {
+				return result, errDecide
+			}
+
+			valueRight, errRight := evaluateRight(row)
+			if errRight != nil {
+				return Value{}, errRight
+			}
+
+			return logicalRight(operator, valueRight)
+		}
+	}
+
+	// apply receives both operands evaluated.
+	var apply func(valueLeft, valueRight Value, row *rowContext) (Value, error)
+
+	switch {
+	case isEqualityOperator(operator):
+		apply = c.compileEqualityWithin(e)
+
+	case operator == _dslIn || operator == _dslNotIn:
+		apply = func(valueLeft, valueRight Value, _ *rowContext) (Value, error) {
+			return evaluateMembership(operator, valueLeft, valueRight)
+		}
+
+	case isStringOperator(operator):
+		apply = func(valueLeft, valueRight Value, _ *rowContext) (Value, error) {
+			return evaluateMatching(operator, e.pattern, valueLeft, valueRight)
+		}
+
+	case isArithmeticOperator(operator):
+		apply = c.compileArithmetic(e)
+
+	default:
+		return compileError(
+			fmt.Errorf(
+				"unsupported binary operator '%s'",
+				operator,
+			),
+		)
+	}
+
+	return func(row *rowContext) (Value, error) {
+		valueLeft, errLeft := evaluateLeft(row)
+		if errLeft != nil {
+			return Value{}, errLeft
+		}
+
+		valueRight, errRight := evaluateRight(row)
+		if errRight != nil {
+			return Value{}, errRight
+		}
+
+		return apply(valueLeft, valueRight, row)
+	}
+}
+
+// compileEqualityWithin compiles '==' and '!=' with a 'within' tolerance,
+// the ones without are conditions, see compileBool.
+func (c *compiler) compileEqualityWithin(e *ExpressionBinary) func(Value, Value, *rowContext) (Value, error) {
+	isEqualOperator := e.Operator == "=="
+	tolerance := c.compileExpression(e.Tolerance)
+
+	return func(valueLeft, valueRight Value, row *rowContext) (Value, error) {
+		valueTolerance, errEvaluate := tolerance(row)
+		if errEvaluate != nil {
+			return Value{},
+				fmt.Errorf(
+					"failed to evaluate tolerance of '%s': %w",
+					e.Operator,
+					errEvaluate,
+				)
+		}
+
+		isEqual, errEqual := equalWithin(e.Operator, valueLeft, valueRight, valueTolerance)
+		if errEqual != nil {
+			return Value{}, errEqual
+		}
+
+		return boolValue(isEqual == isEqualOperator), nil
+	}
+}
+
+func (c *compiler) compileArithmetic(e *ExpressionBinary) func(Value, Value, *rowContext) (Value, error) {
+	var compute func(a, b float64) float64
+
+	switch e.Operator {
+	case "+":
+		compute = func(a, b float64) float64 { return a + b }
+	case "-":
+		compute = func(a, b float64) float64 { return a - b }
+	case "*":
+		compute = func(a, b float64) float64 { return a * b }
+
+	default: // "/", division by zero is left to the generic helpers
+		compute = func(a, b float64) float64 { return a / b }
+	}
+
+	isDivision := e.Operator == "/"
+
+	return func(valueLeft, valueRight Value, _ *rowContext) (Value, error) {
+		if valueLeft.kind == KindNumber && valueRight.kind == KindNumber &&
+			!(isDivision && valueRight.number == 0) {
+			return numberValue(compute(valueLeft.number, valueRight.number)), nil
+		}
+
+		if c.info.isChecked(e) {
+			return evaluateChecked(e, valueLeft, valueRight)
+		}
+
+		return evaluateArithmetic(e.Operator, valueLeft, valueRight)
+	}
+}
+
+// compileBool compiles the operations producing a bool, if the expression
+// is one, straight to a condition: no Value is built for their result.
+func (c *compiler) compileBool(expression Expression) (evaluatorCondition, bool) {
+	switch e := expression.(type) {
+	case *ExpressionLiteral:
+		if e.Value.Kind() != KindBool {
+			return nil, false
+		}
+
+		result := e.Value.boolean
+
+		return func(*rowContext) (bool, error) {
+			return result, nil
+		}, true
+
+	case *ExpressionUnary:
+		if e.Operator != _dslNot && e.Operator != "!" {
+			return nil, false
+		}
+
+		operand, isCondition := c.compileBool(e.Operand)
+		if !isCondition {
+			return nil, false
+		}
+
+		return func(row *rowContext) (bool, error) {
+			result, errEvaluate := operand(row)
+			if errEvaluate != nil {
+				return false,
+					fmt.Errorf(
+						"failed to evaluate operand of '%s': %w",
+						e.Operator,
+						errEvaluate,
+					)
+			}
+
+			return !result, nil
+		}, true
+
+	case *ExpressionBetween:
+		return c.compileBoolBetween(e), true
+
+	case *ExpressionBinary:
+		switch {
+		case isLogicalOperator(e.Operator):
+			return c.compileBoolLogical(e)
+
+		case isOrderingOperator(e.Operator):
+			return c.compileBoolOrdering(e), true
+
+		case isEqualityOperator(e.Operator) && e.Tolerance == nil:
+			return c.compileBoolEquality(e), true
+
+		case e.Operator == _dslIn || e.Operator == _dslNotIn:
+			return c.compileBoolMembership(e)
+		}
+	}
+
+	return nil, false
+}
+
+func (c *compiler) compileBoolLogical(e *ExpressionBinary) (evaluatorCondition, bool) {
+	left, isLeftCondition := c.compileBool(e.LefthandSide)
+	right, isRightCondition := c.compileBool(e.RighthandSide)
+
+	if !isLeftCondition || !isRightCondition {
+		return nil, false
+	}
+
+	isAnd := e.Operator == _dslAnd
+
+	return func(row *rowContext) (bool, error) {
+		resultLeft, errLeft := left(row)
+		if errLeft != nil {
+			return false,
+				fmt.Errorf(
+					"failed to evaluate left side of '%s': %w",
+					e.Operator,
+					errLeft,
+				)
+		}
+
+		if resultLeft != isAnd {
+			return resultLeft, nil // false and ..., true or ...
+		}
+
+		resultRight, errRight := right(row)
+		if errRight != nil {
+			return false,
+				fmt.Errorf(
+					"failed to evaluate right side of '%s': %w",
+					e.Operator,
+					errRight,
+				)
+		}
+
+		return resultRight, nil
+	}, true
+}
+
+// operandDirect is an operand read without a call: 'value' or a literal.
+type operandDirect struct {
+	isValue  bool
+	constant Value
+}
+
+func directOperand(expression Expression) (operandDirect, bool) {
+	switch e := expression.(type) {
+	case *ExpressionLiteral:
+		return operandDirect{constant: e.Value}, true
+
+	case *ExpressionVariable:
+		return operandDirect{isValue: true}, e.Name == _dslValue
+
+	default:
+		return operandDirect{}, false
+	}
+}
+
+func (o *operandDirect) get(row *rowContext) *Value {
+	if o.isValue {
+		return &row.value
+	}
+
+	return &o.constant
+}
+
+// compileBoolOrdering compares two numbers inline, other kinds go through
+// the generic helpers for their result or error.
+func (c *compiler) compileBoolOrdering(e *ExpressionBinary) evaluatorCondition {
+	orderValues := func(valueLeft, valueRight *Value) (bool, error) {
+		if c.info.isChecked(e) {
+			result, errEvaluate := evaluateChecked(e, *valueLeft, *valueRight)
+
+			return result.boolean, errEvaluate
+		}
+
+		result, errEvaluate := evaluateOrdering(e.Operator, *valueLeft, *valueRight)
+
+		return result.boolean, errEvaluate
+	}
+
+	left, isLeftDirect := directOperand(e.LefthandSide)
+	right, isRightDirect := directOperand(e.RighthandSide)
+
+	if isLeftDirect && isRightDirect {
+		return func(row *rowContext) (bool, error) {
+			valueLeft, valueRight := left.get(row), right.get(row)
+
+			if valueLeft.kind == KindNumber && valueRight.kind == KindNumber {
+				return orderNumbers(e.Operator, valueLeft.number, valueRight.number), nil
+			}
+
+			return orderValues(valueLeft, valueRight)
+		}
+	}
+
+	evaluateLeft, evaluateRight := c.compileSides(e)
+
+	return func(row *rowContext) (bool, error) {
+		valueLeft, errLeft := evaluateLeft(row)
+		if errLeft != nil {
+			return false, errLeft
+		}
+
+		valueRight, errRight := evaluateRight(row)
+		if errRight != nil {
+			return false, errRight
+		}
+
+		if valueLeft.kind == KindNumber && valueRight.kind == KindNumber {
+			return orderNumbers(e.Operator, valueLeft.number, valueRight.number), nil
+		}
+
+		return orderValues(&valueLeft, &valueRight)
+	}
+}
+
+func (c *compiler) compileBoolEquality(e *ExpressionBinary) evaluatorCondition {
+	isEqualOperator := e.Operator == "=="
+
+	left, isLeftDirect := directOperand(e.LefthandSide)
+	right, isRightDirect := directOperand(e.RighthandSide)
+
+	if isLeftDirect && isRightDirect {
+		return func(row *rowContext) (bool, error) {
+			return valuesEqual(*left.get(row), *right.get(row)) == isEqualOperator, nil
+		}
+	}
+
+	evaluateLeft, evaluateRight := c.compileSides(e)
+
+	return func(row *rowContext) (bool, error) {
+		valueLeft, errLeft := evaluateLeft(row)
+		if errLeft != nil {
+			return false, errLeft
+		}
+
+		valueRight, errRight := evaluateRight(row)
+		if errRight != nil {
+			return false, errRight
+		}
+
+		return valuesEqual(valueLeft, valueRight) == isEqualOperator, nil
+	}
+}
+
+// compileBoolMembership compiles 'in' against a list of literals,
+// the other lists are built per row by the generic evaluator.
+func (c *compiler) compileBoolMembership(e *ExpressionBinary) (evaluatorCondition, bool) {
+	list, isList := e.RighthandSide.(*ExpressionList)
+	if !isList {
+		return nil, false
+	}
+
+	elements := make([]Value, len(list.Elements))
+
+	for ix, element := range list.Elements {
+		literal, isLiteral := element.(*ExpressionLiteral)
+		if !isLiteral {
+			return nil, false
+		}
+
+		elements[ix] = literal.Value
+	}
+
+	isIn := e.Operator == _dslIn
+	evaluateLeft, _ := c.compileSides(e)
+
+	return func(row *rowContext) (bool, error) {
+		valueLeft, errLeft := evaluateLeft(row)
+		if errLeft != nil {
+			return false, errLeft
+		}
+
+		for ix := range elements {
+			if valuesEqual(valueLeft, elements[ix]) {
+				return isIn, nil
+			}
+		}
+
+		return !isIn, nil
+	}, true
+}
+
+func (c *compiler) compileBoolBetween(e *ExpressionBetween) evaluatorCondition {
+	operand, isOperandDirect := directOperand(e.Operand)
+	lower, isLowerDirect := directOperand(e.Lower)
+	upper, isUpperDirect := directOperand(e.Upper)
+
+	if isOperandDirect && isLowerDirect && isUpperDirect {
+		return func(row *rowContext) (bool, error) {
+			valueOperand := operand.get(row)
+			valueLower, valueUpper := lower.get(row), upper.get(row)
+
+			if valueOperand.kind == KindNumber &&
+				valueLower.kind == KindNumber &&
+				valueUpper.kind == KindNumber {
+				// ordered like evaluateBetween, NaN included
+				isBetween := orderNumbers(">=", valueOperand.number, valueLower.number) &&
+					orderNumbers("<=", valueOperand.number, valueUpper.number)
+
+				return isBetween != e.IsNegated, nil
+			}
+
+			result, errEvaluate := betweenResult(e.IsNegated, [3]Value{*valueOperand, *valueLower, *valueUpper})
+
+			return result.boolean, errEvaluate
+		}
+	}
+
+	evaluate := c.compileBetween(e)
+
+	return func(row *rowContext) (bool, error) {
+		result, errEvaluate := evaluate(row)
+
+		return result.boolean, errEvaluate
+	}
+}
@@ -0,0 +1,494 @@
+package dslalert
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// CompileBytecode compiles the rule conditions of a configuration to
+// bytecode for the VM. Rules are laid out by level descending, the order
+// they are tried in, conditions optimized first; error nodes of tolerant
+// parsing are skipped.
+// Conditions evaluate as the tree evaluator does, errors carrying the
+// message of the failing operation only.
+func CompileBytecode(configuration *AlertConfiguration) (*Bytecode, error) {
+	if configuration == nil {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "CompileBytecode",
+				Issue: goerrors.ErrNilInput{
+					InputName: "configuration",
+				},
+			}
+	}
+
+	c := newBytecodeCompiler()
+
+	for _, criteria := range configuration.Criterias {
+		if criteria.IsError {
+			continue
+		}
+
+		compiled := bytecodeCriteria{
+			name: criteria.Name,
+			doc:  criteria.Doc,
+		}
+
+		for _, monitor := range criteria.Monitors {
+			if monitor.IsError {
+				continue
+			}
+
+			compiledMonitor := bytecodeMonitor{
+				columnName: monitor.ColumnName,
+				doc:        monitor.Doc,
+			}
+
+			for _, rule := range rulesByLevel(monitor) {
+				entry, errCompile := c.condition(optimizeCondition(rule.Condition))
+				if errCompile != nil {
+					return nil,
+						fmt.Errorf(
+							"criteria '%s', monitor '%s', level %d: %w",
+							criteria.Name,
+							monitor.ColumnName,
+							rule.Level,
+							errCompile,
+						)
+				}
+
+				compiledMonitor.rules = append(
+					compiledMonitor.rules,
+
+					bytecodeRule{
+						level: rule.Level,
+						doc:   rule.Doc,
+						entry: entry,
+					},
+				)
+			}
+
+			compiled.monitors = append(compiled.monitors, compiledMonitor)
+		}
+
+		c.result.criterias = append(c.result.criterias, compiled)
+	}
+
+	return c.result, nil
+}
+
+type bytecodeCompiler struct {
+	result *Bytecode
+
+	indexConstants map[constantKey]int // scalar constants only
+	indexNames     map[string]int
+	indexFunctions map[string]int
+
+	depth int // stack depth after the instructions emitted so far
+}
+
+func newBytecodeCompiler() *bytecodeCompiler {
+	return &bytecodeCompiler{
+		result: &Bytecode{},
+
+		indexConstants: make(map[constantKey]int),
+		indexNames:     make(map[string]int),
+		indexFunctions: make(map[string]int),
+	}
+}
+
+// constantKey identifies a scalar constant of the pool.
+type constantKey struct {
+	kind Kind
+
+	number  float64
+	text    string
+	boolean bool
+}
+
+// errPoolFull reports a program beyond the reach of uint16 operands.
+var errPoolFull = errors.New("bytecode too large: more than 65535 entries or code bytes")
+
+// condition compiles a rule condition ending with opReturn, returning
+// where it starts in the code.
+func (c *bytecodeCompiler) condition(condition Expression) (int, error) {
+	entry := len(c.result.code)
+
+	c.depth = 0
+
+	if errCompile := c.expression(condition); errCompile != nil {
+		return 0, errCompile
+	}
+
+	c.emit(opReturn, -1)
+
+	if len(c.result.code) > math.MaxUint16 {
+		return 0, errPoolFull
+	}
+
+	return entry, nil
+}
+
+// emit appends an instruction changing the stack depth by effect,
+// returning the position of its first operand for patching jumps.
+func (c *bytecodeCompiler) emit(op opcode, effect int, operands ...int) int {
+	c.result.code = append(c.result.code, byte(op))
+
+	position := len(c.result.code)
+
+	for _, operand := range operands {
+		c.result.code = binary.BigEndian.AppendUint16(c.result.code, uint16(operand))
+	}
+
+	c.depth += effect
+	c.result.stackMaximum = max(c.result.stackMaximum, c.depth)
+
+	return position
+}
+
+// patch points the jump operand at position to the next instruction.
+func (c *bytecodeCompiler) patch(position int) {
+	binary.BigEndian.PutUint16(c.result.code[position:], uint16(len(c.result.code)))
+}
+
+func (c *bytecodeCompiler) constant(value Value) (int, error) {
+	key := constantKey{
+		kind:    value.kind,
+		number:  value.number,
+		text:    value.text,
+		boolean: value.boolean,
+	}
+
+	if value.kind != KindList {
+		if ix, exists := c.indexConstants[key]; exists {
+			return ix, nil
+		}
+	}
+
+	ix := len(c.result.constants)
+	if ix > math.MaxUint16 {
+		return 0, errPoolFull
+	}
+
+	c.result.constants = append(c.result.constants, value)
+
+	if value.kind != KindList {
+		c.indexConstants[key] = ix
+	}
+
+	return ix, nil
+}
+
+func (c *bytecodeCompiler) name(name string) (int, error) {
+	if ix, exists := c.indexNames[name]; exists {
+		return ix, nil
+	}
+
+	ix := len(c.result.names)
+	if ix > math.MaxUint16 {
+		return 0, errPoolFull
+	}
+
+	c.result.names = append(c.result.names, name)
+	c.indexNames[name] = ix
+
+	return ix, nil
+}
+
+func (c *bytecodeCompiler) function(call *ExpressionCall) (int, error) {
+	// calls built outside Parse, e.g. by Transform, are not resolved.
+	if call.function == nil {
+		return 0,
+			fmt.Errorf(
+				"function '%s' is not resolved",
+				call.Name,
+			)
+	}
+
+	if ix, exists := c.indexFunctions[call.Name]; exists {
+		return ix, nil
+	}
+
+	ix := len(c.result.functions)
+	if ix > math.MaxUint16 {
+		return 0, errPoolFull
+	}
+
+	c.result.functions = append(
+		c.result.functions,
+
+		bytecodeFunction{
+			name:     call.Name,
+			function: call.function,
+		},
+	)
+
+	c.indexFunctions[call.Name] = ix
+
+	return ix, nil
+}
+
+func (c *bytecodeCompiler) expressions(expressions ...Expression) error {
+	for _, expression := range expressions {
+		if errCompile := c.expression(expression); errCompile != nil {
+			return errCompile
+		}
+	}
+
+	return nil
+}
+
+func (c *bytecodeCompiler) expression(expression Expression) error {
+	switch e := expression.(type) {
+	case *ExpressionLiteral:
+		return c.pushConstant(e.Value)
+
+	case *ExpressionVariable:
+		if e.Name == _dslValue {
+			c.emit(opValue, 1)
+
+			return nil
+		}
+
+		ix, errName := c.name(e.Name)
+		if errName != nil {
+			return errName
+		}
+
+		c.emit(opColumn, 1, ix)
+
+		return nil
+
+	case *ExpressionUnary:
+		if errCompile := c.expression(e.Operand); errCompile != nil {
+			return errCompile
+		}
+
+		switch e.Operator {
+		case _dslNot, "!":
+			c.emit(opNot, 0)
+		case "-":
+			c.emit(opNegate, 0)
+		case "+":
+			c.emit(opPlus, 0)
+
+		default:
+			return fmt.Errorf(
+				"unsupported unary operator '%s'",
+				e.Operator,
+			)
+		}
+
+		return nil
+
+	case *ExpressionCall:
+		ix, errFunction := c.function(e)
+		if errFunction != nil {
+			return errFunction
+		}
+
+		if errCompile := c.expressions(e.Arguments...); errCompile != nil {
+			return errCompile
+		}
+
+		c.emit(opCall, 1-len(e.Arguments), ix, len(e.Arguments))
+
+		return nil
+
+	case *ExpressionList:
+		return c.list(e)
+
+	case *ExpressionBetween:
+		if errCompile := c.expressions(e.Operand, e.Lower, e.Upper); errCompile != nil {
+			return errCompile
+		}
+
+		if e.IsNegated {
+			c.emit(opNotBetween, -2)
+		} else {
+			c.emit(opBetween, -2)
+		}
+
+		return nil
+
+	case *ExpressionConditional:
+		return c.conditional(e)
+
+	case *ExpressionBinary:
+		return c.binary(e)
+
+	default:
+		return fmt.Errorf(
+			"unsupported expression type %T",
+			expression,
+		)
+	}
+}
+
+func (c *bytecodeCompiler) pushConstant(value Value) error {
+	ix, errConstant := c.constant(value)
+	if errConstant != nil {
+		return errConstant
+	}
+
+	c.emit(opConstant, 1, ix)
+
+	return nil
+}
+
+// list pushes a list of literals as one constant.
+func (c *bytecodeCompiler) list(e *ExpressionList) error {
+	constants := make([]Value, len(e.Elements))
+
+	for ix, element := range e.Elements {
+		literal, isLiteral := element.(*ExpressionLiteral)
+		if !isLiteral {
+			if errCompile := c.expressions(e.Elements...); errCompile != nil {
+				return errCompile
+			}
+
+			c.emit(opList, 1-len(e.Elements), len(e.Elements))
+
+			return nil
+		}
+
+		constants[ix] = literal.Value
+	}
+
+	return c.pushConstant(listValue(constants))
+}
+
+func (c *bytecodeCompiler) conditional(e *ExpressionConditional) error {
+	if errCompile := c.expression(e.Condition); errCompile != nil {
+		return errCompile
+	}
+
+	jumpAlternative := c.emit(opJumpIfNotThen, -1, 0)
+
+	if errCompile := c.expression(e.Consequence); errCompile != nil {
+		return errCompile
+	}
+
+	jumpEnd := c.emit(opJump, 0, 0)
+
+	// the alternative starts from the depth before the consequence.
+	c.depth--
+
+	c.patch(jumpAlternative)
+
+	if errCompile := c.expression(e.Alternative); errCompile != nil {
+		return errCompile
+	}
+
+	c.patch(jumpEnd)
+
+	return nil
+}
+
+var _binaryOpcodes = map[string]opcode{
+	"+": opAdd,
+	"-": opSubtract,
+	"*": opMultiply,
+	"/": opDivide,
+
+	">":  opGreater,
+	">=": opGreaterEqual,
+	"<":  opLess,
+	"<=": opLessEqual,
+	"==": opEqual,
+	"!=": opNotEqual,
+
+	_dslIn:    opIn,
+	_dslNotIn: opNotIn,
+
+	_dslContains:   opContains,
+	_dslStartsWith: opStartsWith,
+	_dslEndsWith:   opEndsWith,
+}
+
+func (c *bytecodeCompiler) binary(e *ExpressionBinary) error {
+	if errCompile := c.expression(e.LefthandSide); errCompile != nil {
+		return errCompile
+	}
+
+	if isLogicalOperator(e.Operator) {
+		return c.logical(e)
+	}
+
+	if errCompile := c.expression(e.RighthandSide); errCompile != nil {
+		return errCompile
+	}
+
+	if e.Tolerance != nil {
+		if errCompile := c.expression(e.Tolerance); errCompile != nil {
+			return errCompile
+		}
+
+		if e.Operator == "==" {
+			c.emit(opEqualWithin, -2)
+		} else {
+			c.emit(opNotEqualWithin, -2)
+		}
+
+		return nil
+	}
+
+	if e.Operator == _dslMatches {
+		if e.pattern == nil {
+			return fmt.Errorf(
+				"pattern of '%s' is not compiled",
+				_dslMatches,
+			)
+		}
+
+		c.result.patterns = append(c.result.patterns, e.pattern)
+		if len(c.result.patterns) > math.MaxUint16 {
+			return errPoolFull
+		}
+
+		c.emit(opMatches, -1, len(c.result.patterns)-1)
+
+		return nil
+	}
+
+	op, isSupported := _binaryOpcodes[e.Operator]
+	if !isSupported {
+		return fmt.Errorf(
+			"unsupported binary operator '%s'",
+			e.Operator,
+		)
+	}
+
+	c.emit(op, -1)
+
+	return nil
+}
+
+// logical compiles the right side of 'and' / 'or' after a jump taken when
+// the left side decides the result.
+func (c *bytecodeCompiler) logical(e *ExpressionBinary) error {
+	ixOperator, errName := c.name(e.Operator)
+	if errName != nil {
+		return errName
+	}
+
+	op := opJumpIfTrueOrPop
+	if e.Operator == _dslAnd {
+		op = opJumpIfFalseOrPop
+	}
+
+	jumpEnd := c.emit(op, -1, 0)
+
+	if errCompile := c.expression(e.RighthandSide); errCompile != nil {
+		return errCompile
+	}
+
+	c.emit(opLogicalRight, 0, ixOperator)
+	c.patch(jumpEnd)
+
+	return nil
+}
@@ -0,0 +1,371 @@
+package dslalert
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+const (
+	stepsMaximumDefault = 10_000 // instructions per condition
+	stackMaximumDefault = 256    // values on the stack
+)
+
+// ErrExecutionLimit is returned, wrapped, by conditions running over
+// the limits of their VM.
+var ErrExecutionLimit = errors.New("execution limit exceeded")
+
+// ParamsVM configures NewVM. Zero values take the defaults.
+type ParamsVM struct {
+	StepsMaximum int // instructions per condition, defaults to 10000
+	StackMaximum int // values on the stack, defaults to 256
+}
+
+// VM runs bytecode with a stack of its own, reused for every condition.
+// A VM is not safe for concurrent use, use one per goroutine:
+// the Bytecode itself can be shared.
+type VM struct {
+	bytecode *Bytecode
+	stack    []Value
+
+	stepsMaximum int
+}
+
+// NewVM prepares a VM for the bytecode, refusing bytecode that needs
+// a deeper stack than allowed.
+func NewVM(bytecode *Bytecode, params *ParamsVM) (*VM, error) {
+	if bytecode == nil {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "NewVM",
+				Issue: goerrors.ErrNilInput{
+					InputName: "bytecode",
+				},
+			}
+	}
+
+	if params == nil {
+		params = &ParamsVM{}
+	}
+
+	stepsMaximum := params.StepsMaximum
+	if stepsMaximum <= 0 {
+		stepsMaximum = stepsMaximumDefault
+	}
+
+	stackMaximum := params.StackMaximum
+	if stackMaximum <= 0 {
+		stackMaximum = stackMaximumDefault
+	}
+
+	if bytecode.stackMaximum > stackMaximum {
+		return nil,
+			fmt.Errorf(
+				"bytecode needs a stack of %d values, more than %d: %w",
+				bytecode.stackMaximum,
+				stackMaximum,
+				ErrExecutionLimit,
+			)
+	}
+
+	return &VM{
+			bytecode:     bytecode,
+			stack:        make([]Value, 0, stackMaximum),
+			stepsMaximum: stepsMaximum,
+		},
+		nil
+}
+
+// EvaluateCriteria evaluates the criteria of the bytecode with the given
+// name over a CSV dataset, with the results of the package EvaluateCriteria.
+func (vm *VM) EvaluateCriteria(name string, dataset []string) (EvaluationResults, error) {
+	for _, criteria := range vm.bytecode.criterias {
+		if criteria.name == name {
+			return vm.criteria(&criteria).evaluate(dataset)
+		}
+	}
+
+	return nil,
+		goerrors.ErrEntryNotFound{
+			Key: name,
+		}
+}
+
+func (vm *VM) criteria(criteria *bytecodeCriteria) *criteriaCompiled {
+	result := criteriaCompiled{
+		name:     criteria.name,
+		doc:      criteria.doc,
+		monitors: make([]monitorCompiled, len(criteria.monitors)),
+	}
+
+	for ixMonitor, monitor := range criteria.monitors {
+		compiled := monitorCompiled{
+			columnName: monitor.columnName,
+			doc:        monitor.doc,
+			rules:      make([]ruleCompiled, len(monitor.rules)),
+		}
+
+		for ixRule, rule := range monitor.rules {
+			entry := rule.entry
+
+			compiled.rules[ixRule] = ruleCompiled{
+				level: rule.level,
+				doc:   rule.doc,
+				condition: func(row *rowContext) (bool, error) {
+					return vm.run(entry, row)
+				},
+			}
+		}
+
+		result.monitors[ixMonitor] = compiled
+	}
+
+	return &result
+}
+
+func (vm *VM) push(value Value) error {
+	if len(vm.stack) == cap(vm.stack) {
+		return fmt.Errorf(
+			"stack of %d values is full: %w",
+			cap(vm.stack),
+			ErrExecutionLimit,
+		)
+	}
+
+	vm.stack = append(vm.stack, value)
+
+	return nil
+}
+
+func (vm *VM) pop() Value {
+	result := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+
+	return result
+}
+
+// top points at the value on the top of the stack, replaced in place
+// by unary and binary operations.
+func (vm *VM) top() *Value {
+	return &vm.stack[len(vm.stack)-1]
+}
+
+// run executes the condition starting at entry for the row.
+func (vm *VM) run(entry int, row *rowContext) (bool, error) {
+	vm.stack = vm.stack[:0]
+
+	code := vm.bytecode.code
+
+	pc := entry
+
+	operand := func() int {
+		result := int(binary.BigEndian.Uint16(code[pc:]))
+		pc += 2
+
+		return result
+	}
+
+	for steps := 0; ; steps++ {
+		if steps == vm.stepsMaximum {
+			return false,
+				fmt.Errorf(
+					"condition ran more than %d instructions: %w",
+					vm.stepsMaximum,
+					ErrExecutionLimit,
+				)
+		}
+
+		op := opcode(code[pc])
+		pc++
+
+		var errStep error
+
+		switch op {
+		case opConstant:
+			errStep = vm.push(vm.bytecode.constants[operand()])
+
+		case opValue:
+			errStep = vm.push(row.value)
+
+		case opColumn:
+			valueColumn, errColumn := row.column(vm.bytecode.names[operand()])
+			if errColumn != nil {
+				return false, errColumn
+			}
+
+			errStep = vm.push(valueColumn)
+
+		case opNot, opNegate, opPlus:
+			*vm.top(), errStep = unaryResult(_opcodeOperators[op], *vm.top())
+
+		case opAdd, opSubtract, opMultiply, opDivide:
+			valueRight := vm.pop()
+
+			*vm.top(), errStep = evaluateArithmetic(_opcodeOperators[op], *vm.top(), valueRight)
+
+		case opGreater, opGreaterEqual, opLess, opLessEqual:
+			valueRight := vm.pop()
+
+			*vm.top(), errStep = evaluateOrdering(_opcodeOperators[op], *vm.top(), valueRight)
+
+		case opEqual, opNotEqual:
+			valueRight := vm.pop()
+
+			*vm.top() = boolValue(valuesEqual(*vm.top(), valueRight) == (op == opEqual))
+
+		case opEqualWithin, opNotEqualWithin:
+			valueTolerance := vm.pop()
+			valueRight := vm.pop()
+
+			isEqual, errEqual := equalWithin(_opcodeOperators[op], *vm.top(), valueRight, valueTolerance)
+
+			*vm.top(), errStep = boolValue(isEqual == (op == opEqualWithin)), errEqual
+
+		case opIn, opNotIn:
+			valueRight := vm.pop()
+
+			*vm.top(), errStep = evaluateMembership(_opcodeOperators[op], *vm.top(), valueRight)
+
+		case opBetween, opNotBetween:
+			valueUpper := vm.pop()
+			valueLower := vm.pop()
+
+			*vm.top(), errStep = betweenResult(
+				op == opNotBetween,
+				[3]Value{*vm.top(), valueLower, valueUpper},
+			)
+
+		case opContains, opStartsWith, opEndsWith:
+			valueRight := vm.pop()
+
+			*vm.top(), errStep = evaluateMatching(_opcodeOperators[op], nil, *vm.top(), valueRight)
+
+		case opMatches:
+			pattern := vm.bytecode.patterns[operand()]
+			valueRight := vm.pop()
+
+			*vm.top(), errStep = evaluateMatching(_dslMatches, pattern, *vm.top(), valueRight)
+
+		case opList:
+			count := operand()
+			elements := make([]Value, count)
+
+			copy(elements, vm.stack[len(vm.stack)-count:])
+			vm.stack = vm.stack[:len(vm.stack)-count]
+
+			errStep = vm.push(listValue(elements))
+
+		case opCall:
+			errStep = vm.call(operand(), operand())
+
+		case opJump:
+			pc = operand()
+
+		case opJumpIfFalseOrPop, opJumpIfTrueOrPop:
+			operator := _dslOr
+			if op == opJumpIfFalseOrPop {
+				operator = _dslAnd
+			}
+
+			target := operand()
+
+			result, isDecided, errLeft := logicalLeft(operator, *vm.top())
+			if errLeft != nil {
+				return false, errLeft
+			}
+
+			if isDecided {
+				*vm.top() = result
+				pc = target
+
+				continue
+			}
+
+			vm.pop()
+
+		case opLogicalRight:
+			*vm.top(), errStep = logicalRight(vm.bytecode.names[operand()], *vm.top())
+
+		case opJumpIfNotThen:
+			target := operand()
+
+			isConsequence, errCondition := conditionalChoice(vm.pop())
+			if errCondition != nil {
+				return false, errCondition
+			}
+
+			if !isConsequence {
+				pc = target
+			}
+
+		case opReturn:
+			return conditionResult(vm.pop())
+
+		default:
+			return false,
+				fmt.Errorf(
+					"invalid opcode %d at %04d",
+					op,
+					pc-1,
+				)
+		}
+
+		if errStep != nil {
+			return false, errStep
+		}
+	}
+}
+
+// call pops the arguments of a function and pushes its result.
+func (vm *VM) call(ixFunction, count int) error {
+	called := vm.bytecode.functions[ixFunction]
+
+	expr := &ExpressionCall{
+		Name:     called.name,
+		function: called.function,
+	}
+
+	arguments := vm.stack[len(vm.stack)-count:]
+
+	var (
+		result    Value
+		errResult error
+	)
+
+	if called.function.implementationNumeric != nil {
+		numbers := make([]float64, count)
+
+		for ix, valueArgument := range arguments {
+			number, errArgument := numericArgument(expr, ix, valueArgument)
+			if errArgument != nil {
+				return errArgument
+			}
+
+			numbers[ix] = number
+		}
+
+		result, errResult = callNumeric(expr, numbers)
+	} else {
+		values := make([]any, count)
+
+		for ix, valueArgument := range arguments {
+			if errArgument := hostArgument(expr, ix, valueArgument); errArgument != nil {
+				return errArgument
+			}
+
+			values[ix] = valueArgument.any()
+		}
+
+		result, errResult = callHost(expr, values)
+	}
+
+	if errResult != nil {
+		return errResult
+	}
+
+	vm.stack = vm.stack[:len(vm.stack)-count]
+
+	return vm.push(result)
+}
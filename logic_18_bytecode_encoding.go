@@ -0,0 +1,628 @@
+package dslalert
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// bytecodeVersion changes with the instruction set or the encoding,
+// invalidating bytecode saved by previous versions.
+const bytecodeVersion = 1
+
+// HashConfiguration returns a key for caching the bytecode of a
+// configuration: configurations formatting the same share the key.
+func HashConfiguration(configuration *AlertConfiguration) string {
+	hash := sha256.New()
+
+	fmt.Fprintf(hash, "dslalert bytecode v%d\n", bytecodeVersion)
+	hash.Write([]byte(Format(configuration)))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// the encoded forms mirror the unexported fields of Bytecode.
+type (
+	bytecodeFile struct {
+		Version int
+
+		Code      []byte
+		Constants []valueFile
+		Names     []string
+		Patterns  []string
+		Functions []string
+
+		Criterias []criteriaFile
+	}
+
+	valueFile struct {
+		Kind Kind
+
+		Number  float64
+		Text    string
+		Boolean bool
+		List    []valueFile
+	}
+
+	criteriaFile struct {
+		Name string
+		Doc  string
+
+		Monitors []monitorFile
+	}
+
+	monitorFile struct {
+		ColumnName string
+		Doc        string
+
+		Rules []ruleFile
+	}
+
+	ruleFile struct {
+		Level int
+		Doc   string
+		Entry int
+	}
+)
+
+func newValueFile(value Value) valueFile {
+	result := valueFile{
+		Kind:    value.kind,
+		Number:  value.number,
+		Text:    value.text,
+		Boolean: value.boolean,
+	}
+
+	for _, element := range value.list {
+		result.List = append(result.List, newValueFile(element))
+	}
+
+	return result
+}
+
+func (f valueFile) value() Value {
+	result := Value{
+		kind:    f.Kind,
+		number:  f.Number,
+		text:    f.Text,
+		boolean: f.Boolean,
+	}
+
+	if f.Kind == KindList {
+		result.list = make([]Value, len(f.List))
+
+		for ix, element := range f.List {
+			result.list[ix] = element.value()
+		}
+	}
+
+	return result
+}
+
+// MarshalBinary encodes the bytecode for UnmarshalBytecode. Functions are
+// saved by name and patterns by source, both resolved again on load.
+func (b *Bytecode) MarshalBinary() ([]byte, error) {
+	file := bytecodeFile{
+		Version: bytecodeVersion,
+		Code:    b.code,
+		Names:   b.names,
+	}
+
+	for _, constant := range b.constants {
+		file.Constants = append(file.Constants, newValueFile(constant))
+	}
+
+	for _, pattern := range b.patterns {
+		file.Patterns = append(file.Patterns, pattern.String())
+	}
+
+	for _, called := range b.functions {
+		file.Functions = append(file.Functions, called.name)
+	}
+
+	for _, criteria := range b.criterias {
+		criteriaEncoded := criteriaFile{
+			Name: criteria.name,
+			Doc:  criteria.doc,
+		}
+
+		for _, monitor := range criteria.monitors {
+			monitorEncoded := monitorFile{
+				ColumnName: monitor.columnName,
+				Doc:        monitor.doc,
+			}
+
+			for _, rule := range monitor.rules {
+				monitorEncoded.Rules = append(
+					monitorEncoded.Rules,
+
+					ruleFile{
+						Level: rule.level,
+						Doc:   rule.doc,
+						Entry: rule.entry,
+					},
+				)
+			}
+
+			criteriaEncoded.Monitors = append(criteriaEncoded.Monitors, monitorEncoded)
+		}
+
+		file.Criterias = append(file.Criterias, criteriaEncoded)
+	}
+
+	var buffer bytes.Buffer
+
+	if errEncode := gob.NewEncoder(&buffer).Encode(file); errEncode != nil {
+		return nil, errEncode
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// errBytecodeVersion reports bytecode saved by another version of the package,
+// to be compiled again.
+var errBytecodeVersion = errors.New("bytecode version not supported")
+
+// UnmarshalBytecode decodes bytecode saved with MarshalBinary, resolving
+// host functions against the registry as Parse does: nil for built-ins only.
+// The code is verified before use, so bytecode from an untrusted cache
+// fails here rather than in the VM.
+func UnmarshalBytecode(data []byte, functions *FunctionRegistry) (*Bytecode, error) {
+	var file bytecodeFile
+
+	if errDecode := gob.NewDecoder(bytes.NewReader(data)).Decode(&file); errDecode != nil {
+		return nil,
+			fmt.Errorf(
+				"decode bytecode: %w",
+				errDecode,
+			)
+	}
+
+	if file.Version != bytecodeVersion {
+		return nil,
+			fmt.Errorf(
+				"version %d, expected %d: %w",
+				file.Version,
+				bytecodeVersion,
+				errBytecodeVersion,
+			)
+	}
+
+	result := Bytecode{
+		code:  file.Code,
+		names: file.Names,
+	}
+
+	for _, constant := range file.Constants {
+		result.constants = append(result.constants, constant.value())
+	}
+
+	for _, source := range file.Patterns {
+		pattern, errPattern := regexp.Compile(source)
+		if errPattern != nil {
+			return nil,
+				fmt.Errorf(
+					"invalid pattern %s: %w",
+					source,
+					errPattern,
+				)
+		}
+
+		result.patterns = append(result.patterns, pattern)
+	}
+
+	for _, name := range file.Functions {
+		resolved, exists := functions.lookup(name)
+		if !exists {
+			return nil,
+				goerrors.ErrEntryNotFound{
+					Key: name,
+				}
+		}
+
+		result.functions = append(
+			result.functions,
+
+			bytecodeFunction{
+				name:     name,
+				function: resolved,
+			},
+		)
+	}
+
+	for _, criteria := range file.Criterias {
+		criteriaDecoded := bytecodeCriteria{
+			name: criteria.Name,
+			doc:  criteria.Doc,
+		}
+
+		for _, monitor := range criteria.Monitors {
+			monitorDecoded := bytecodeMonitor{
+				columnName: monitor.ColumnName,
+				doc:        monitor.Doc,
+			}
+
+			for _, rule := range monitor.Rules {
+				monitorDecoded.rules = append(
+					monitorDecoded.rules,
+
+					bytecodeRule{
+						level: rule.Level,
+						doc:   rule.Doc,
+						entry: rule.Entry,
+					},
+				)
+			}
+
+			criteriaDecoded.monitors = append(criteriaDecoded.monitors, monitorDecoded)
+		}
+
+		result.criterias = append(result.criterias, criteriaDecoded)
+	}
+
+	if errVerify := result.verify(); errVerify != nil {
+		return nil,
+			fmt.Errorf(
+				"verify bytecode: %w",
+				errVerify,
+			)
+	}
+
+	return &result, nil
+}
+
+// instruction is an opcode decoded at an offset of the code.
+type instruction struct {
+	offset   int
+	op       opcode
+	operands []int
+}
+
+func (i instruction) next() int {
+	return i.offset + 1 + 2*len(i.operands)
+}
+
+// instructions decodes the code, failing on unknown opcodes
+// and truncated operands.
+func (b *Bytecode) instructions() ([]instruction, error) {
+	var result []instruction
+
+	for offset := 0; offset < len(b.code); {
+		op := opcode(b.code[offset])
+		if op.String() == "UNKNOWN" {
+			return nil,
+				fmt.Errorf(
+					"invalid opcode %d at %04d",
+					op,
+					offset,
+				)
+		}
+
+		decoded := instruction{
+			offset:   offset,
+			op:       op,
+			operands: make([]int, _opcodeOperands[op]),
+		}
+
+		if decoded.next() > len(b.code) {
+			return nil,
+				fmt.Errorf(
+					"truncated %s at %04d",
+					op,
+					offset,
+				)
+		}
+
+		for ix := range decoded.operands {
+			decoded.operands[ix] = int(binary.BigEndian.Uint16(b.code[offset+1+2*ix:]))
+		}
+
+		result = append(result, decoded)
+		offset = decoded.next()
+	}
+
+	return result, nil
+}
+
+// stackEffect returns how many values an instruction pops and pushes.
+func (i instruction) stackEffect() (int, int) {
+	switch i.op {
+	case opConstant, opValue, opColumn:
+		return 0, 1
+	case opNot, opNegate, opPlus, opLogicalRight:
+		return 1, 1
+	case opEqualWithin, opNotEqualWithin, opBetween, opNotBetween:
+		return 3, 1
+	case opList:
+		return i.operands[0], 1
+	case opCall:
+		return i.operands[1], 1
+	case opJump:
+		return 0, 0
+	case opJumpIfFalseOrPop, opJumpIfTrueOrPop, opJumpIfNotThen, opReturn:
+		return 1, 0
+
+	default:
+		return 2, 1
+	}
+}
+
+// verify checks the pools referenced by the code, then follows every
+// condition from its entry to prove jumps land on instructions and the
+// stack never underflows, recomputing stackMaximum on the way.
+func (b *Bytecode) verify() error {
+	decoded, errDecode := b.instructions()
+	if errDecode != nil {
+		return errDecode
+	}
+
+	byOffset := make(map[int]instruction, len(decoded))
+
+	for _, current := range decoded {
+		if errOperands := b.verifyOperands(current); errOperands != nil {
+			return errOperands
+		}
+
+		byOffset[current.offset] = current
+	}
+
+	// depths holds the stack depth before each instruction reached.
+	depths := make(map[int]int, len(decoded))
+
+	b.stackMaximum = 0
+
+	for _, criteria := range b.criterias {
+		for _, monitor := range criteria.monitors {
+			for _, rule := range monitor.rules {
+				if errFlow := b.verifyFlow(rule.entry, byOffset, depths); errFlow != nil {
+					return fmt.Errorf(
+						"criteria '%s', monitor '%s', level %d: %w",
+						criteria.name,
+						monitor.columnName,
+						rule.level,
+						errFlow,
+					)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *Bytecode) verifyOperands(current instruction) error {
+	var size int
+
+	switch current.op {
+	case opConstant:
+		size = len(b.constants)
+	case opColumn, opLogicalRight:
+		size = len(b.names)
+	case opMatches:
+		size = len(b.patterns)
+	case opCall:
+		size = len(b.functions)
+
+	default:
+		return nil
+	}
+
+	if current.operands[0] >= size {
+		return fmt.Errorf(
+			"%s at %04d refers to entry %d of %d",
+			current.op,
+			current.offset,
+			current.operands[0],
+			size,
+		)
+	}
+
+	if current.op == opCall && !b.functions[current.operands[0]].function.acceptsArity(current.operands[1]) {
+		return fmt.Errorf(
+			"%s at %04d passes %d arguments to '%s'",
+			current.op,
+			current.offset,
+			current.operands[1],
+			b.functions[current.operands[0]].name,
+		)
+	}
+
+	if current.op == opLogicalRight && !isLogicalOperator(b.names[current.operands[0]]) {
+		return fmt.Errorf(
+			"%s at %04d refers to '%s', not a logical operator",
+			current.op,
+			current.offset,
+			b.names[current.operands[0]],
+		)
+	}
+
+	return nil
+}
+
+func (b *Bytecode) verifyFlow(entry int, byOffset map[int]instruction, depths map[int]int) error {
+	type pending struct {
+		offset int
+		depth  int
+	}
+
+	work := []pending{{offset: entry}}
+
+	for len(work) > 0 {
+		at := work[len(work)-1]
+		work = work[:len(work)-1]
+
+		current, exists := byOffset[at.offset]
+		if !exists {
+			return fmt.Errorf(
+				"flow reaches %04d, not an instruction",
+				at.offset,
+			)
+		}
+
+		if depth, isVisited := depths[at.offset]; isVisited {
+			if depth != at.depth {
+				return fmt.Errorf(
+					"stack depth %d and %d at %04d",
+					depth,
+					at.depth,
+					at.offset,
+				)
+			}
+
+			continue
+		}
+
+		depths[at.offset] = at.depth
+
+		pops, pushes := current.stackEffect()
+		if at.depth < pops {
+			return fmt.Errorf(
+				"%s at %04d pops %d values from a stack of %d",
+				current.op,
+				current.offset,
+				pops,
+				at.depth,
+			)
+		}
+
+		after := at.depth - pops + pushes
+		b.stackMaximum = max(b.stackMaximum, after)
+
+		switch current.op {
+		case opReturn:
+			if at.depth != 1 {
+				return fmt.Errorf(
+					"%s at %04d with a stack of %d",
+					current.op,
+					current.offset,
+					at.depth,
+				)
+			}
+
+		case opJump:
+			work = append(work, pending{offset: current.operands[0], depth: at.depth})
+
+		case opJumpIfFalseOrPop, opJumpIfTrueOrPop:
+			work = append(
+				work,
+
+				pending{offset: current.operands[0], depth: at.depth},
+				pending{offset: current.next(), depth: after},
+			)
+
+		case opJumpIfNotThen:
+			work = append(
+				work,
+
+				pending{offset: current.operands[0], depth: after},
+				pending{offset: current.next(), depth: after},
+			)
+
+		default:
+			work = append(work, pending{offset: current.next(), depth: after})
+		}
+	}
+
+	return nil
+}
+
+// Disassemble lists the instructions of every rule for debugging, e.g.
+//
+//	criteria 'latency', monitor 'p99', level 2:
+//	0000  VALUE
+//	0001  CONSTANT              0 (500)
+//	0004  GREATER
+//	0005  RETURN
+func (b *Bytecode) Disassemble() string {
+	decoded, errDecode := b.instructions()
+	if errDecode != nil {
+		return errDecode.Error()
+	}
+
+	var result strings.Builder
+
+	for _, criteria := range b.criterias {
+		for _, monitor := range criteria.monitors {
+			for _, rule := range monitor.rules {
+				fmt.Fprintf(
+					&result,
+					"criteria '%s', monitor '%s', level %d:\n",
+
+					criteria.name,
+					monitor.columnName,
+					rule.level,
+				)
+
+				for _, current := range decoded {
+					if current.offset < rule.entry {
+						continue
+					}
+
+					result.WriteString(b.disassembleInstruction(current))
+					result.WriteByte('\n')
+
+					if current.op == opReturn {
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return result.String()
+}
+
+func (b *Bytecode) disassembleInstruction(current instruction) string {
+	if len(current.operands) == 0 {
+		return fmt.Sprintf(
+			"%04d  %s",
+
+			current.offset,
+			current.op,
+		)
+	}
+
+	var detail string
+
+	switch current.op {
+	case opConstant:
+		detail = b.constants[current.operands[0]].String()
+	case opColumn, opLogicalRight:
+		detail = b.names[current.operands[0]]
+	case opMatches:
+		detail = b.patterns[current.operands[0]].String()
+	case opCall:
+		detail = fmt.Sprintf(
+			"%s/%d",
+
+			b.functions[current.operands[0]].name,
+			current.operands[1],
+		)
+	case opList:
+		detail = "elements"
+
+	default:
+		detail = fmt.Sprintf(
+			"-> %04d",
+
+			current.operands[0],
+		)
+	}
+
+	return fmt.Sprintf(
+		"%04d  %-20s  %d (%s)",
+
+		current.offset,
+		current.op,
+		current.operands[0],
+		detail,
+	)
+}
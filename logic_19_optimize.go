@@ -0,0 +1,399 @@
+package dslalert
+
+import (
+	"fmt"
+	"math"
+)
+
+// ParamsOptimize configures OptimizeWithParams.
+type ParamsOptimize struct {
+	// IsDebugMode prints every condition the optimizer changed,
+	// before and after.
+	IsDebugMode bool
+}
+
+// Optimize returns a copy of the configuration with the rule conditions
+// simplified, the input is left unchanged:
+//   - constant subtrees are folded, e.g. 'value > 60 * 60 * 24' to 'value > 86400',
+//     the calls of built-in functions included, host functions excluded;
+//   - comparisons get their literal on the right, e.g. '5 < value' to 'value > 5';
+//   - 'not' moves into equalities, 'between' and 'in', e.g. 'not (value == 5)' to 'value != 5';
+//   - constant factors and terms are grouped, e.g. 'value * 100 / 100' to 'value * 1';
+//   - identities like 'x * 1', 'x - 0', 'true and x' and 'not not x' are eliminated.
+//
+// Simplified conditions match and fail the rows they did before, with error
+// messages possibly worded differently. Grouping constants reorders floating
+// point operations, so results computed from them are rounded differently:
+// factors are only grouped between 2^-256 and 2^256 in magnitude, the rows
+// whose values come within that factor of the float limits may still
+// overflow or underflow differently.
+func Optimize(configuration *AlertConfiguration) *AlertConfiguration {
+	return OptimizeWithParams(configuration, &ParamsOptimize{})
+}
+
+func OptimizeWithParams(configuration *AlertConfiguration, params *ParamsOptimize) *AlertConfiguration {
+	result := Transform(
+		configuration,
+		func(expression Expression) Expression {
+			return expression
+		},
+	).(*AlertConfiguration)
+
+	for _, criteria := range result.Criterias {
+		for _, monitor := range criteria.Monitors {
+			for _, rule := range monitor.Rules {
+				if rule.IsError || rule.Condition == nil {
+					continue
+				}
+
+				before := rule.Condition.String()
+
+				rule.Condition = optimizeCondition(rule.Condition)
+
+				if params.IsDebugMode && rule.Condition.String() != before {
+					fmt.Printf(
+						"OPTIMIZE: criteria %q, monitor %q, level %d: %s => %s\n",
+
+						criteria.Name,
+						monitor.ColumnName,
+						rule.Level,
+						before,
+						rule.Condition,
+					)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// optimizeCondition simplifies a copy of the condition.
+func optimizeCondition(condition Expression) Expression {
+	return transformExpression(condition, simplify)
+}
+
+// simplify rewrites an expression whose children are already simplified.
+func simplify(expression Expression) Expression {
+	if folded, isFolded := fold(expression); isFolded {
+		return folded
+	}
+
+	switch e := expression.(type) {
+	case *ExpressionUnary:
+		return simplifyUnary(e)
+
+	case *ExpressionBinary:
+		return simplifyBinary(e)
+
+	case *ExpressionConditional:
+		if literal, isLiteral := e.Condition.(*ExpressionLiteral); isLiteral && literal.Value.kind == KindBool {
+			if literal.Value.boolean {
+				return e.Consequence
+			}
+
+			return e.Alternative
+		}
+	}
+
+	return expression
+}
+
+// kindOf returns the kind Check infers for the expression, KindAny when
+// only known per row.
+func kindOf(expression Expression) Kind {
+	return newChecker().expression(expression)
+}
+
+func isLiteral(expression Expression) bool {
+	_, isLiteral := expression.(*ExpressionLiteral)
+
+	return isLiteral
+}
+
+// literalNumber returns the number of a number literal.
+func literalNumber(expression Expression) (float64, bool) {
+	literal, isLiteral := expression.(*ExpressionLiteral)
+	if !isLiteral || literal.Value.kind != KindNumber {
+		return 0, false
+	}
+
+	return literal.Value.number, true
+}
+
+func literalAt(node Node, value Value) *ExpressionLiteral {
+	return &ExpressionLiteral{
+		Span:  node.Location(),
+		Value: value,
+		Raw:   value.String(),
+	}
+}
+
+// fold evaluates an operation on literals only. Operations failing are kept
+// to fail per row as before, so are results Format could not print back.
+func fold(expression Expression) (Expression, bool) {
+	switch e := expression.(type) {
+	case *ExpressionLiteral, *ExpressionVariable, *ExpressionList:
+		return nil, false
+
+	case *ExpressionCall:
+		// host functions may depend on more than their arguments.
+		if e.function == nil || e.function.implementationNumeric == nil {
+			return nil, false
+		}
+	}
+
+	for _, child := range children(expression) {
+		if _, isLiteral := child.(*ExpressionLiteral); !isLiteral {
+			return nil, false
+		}
+	}
+
+	value, errEvaluate := evaluateExpression(expression, &rowContext{})
+	if errEvaluate != nil {
+		return nil, false
+	}
+
+	switch value.kind {
+	case KindList:
+		return nil, false
+
+	case KindNumber:
+		if math.IsInf(value.number, 0) || math.IsNaN(value.number) {
+			return nil, false
+		}
+	}
+
+	return literalAt(expression, value), true
+}
+
+func simplifyUnary(e *ExpressionUnary) Expression {
+	switch e.Operator {
+	case "+":
+		if kindOf(e.Operand) == KindNumber {
+			return e.Operand
+		}
+
+	case "-":
+		if inner, isUnary := e.Operand.(*ExpressionUnary); isUnary && inner.Operator == "-" && kindOf(inner.Operand) == KindNumber {
+			return inner.Operand
+		}
+
+	case _dslNot, "!":
+		if negated, isNegated := negate(e.Operand); isNegated {
+			return negated
+		}
+	}
+
+	return e
+}
+
+// _negatedOperators maps the operators whose negation is an operator.
+// Orderings are left out, as against NaN both one and its negation are false.
+var _negatedOperators = map[string]string{
+	"==": "!=",
+	"!=": "==",
+
+	_dslIn:    _dslNotIn,
+	_dslNotIn: _dslIn,
+}
+
+// negate returns the operand of 'not' rewritten to hold when it does not.
+func negate(operand Expression) (Expression, bool) {
+	switch o := operand.(type) {
+	case *ExpressionUnary:
+		if (o.Operator == _dslNot || o.Operator == "!") && kindOf(o.Operand) == KindBool {
+			return o.Operand, true
+		}
+
+	case *ExpressionBinary:
+		if operator, exists := _negatedOperators[o.Operator]; exists {
+			result := *o
+			result.Operator = operator
+
+			return &result, true
+		}
+
+	case *ExpressionBetween:
+		result := *o
+		result.IsNegated = !o.IsNegated
+
+		return &result, true
+	}
+
+	return nil, false
+}
+
+func simplifyBinary(e *ExpressionBinary) Expression {
+	if isLogicalOperator(e.Operator) {
+		return simplifyLogical(e)
+	}
+
+	if isComparisonOperator(e.Operator) && isLiteral(e.LefthandSide) && !isLiteral(e.RighthandSide) {
+		result := *e
+		result.Operator = mirrorComparison(e.Operator)
+		result.LefthandSide, result.RighthandSide = e.RighthandSide, e.LefthandSide
+
+		e = &result
+	}
+
+	if !isArithmeticOperator(e.Operator) {
+		return e
+	}
+
+	if grouped, isGrouped := group(e); isGrouped {
+		return simplify(grouped)
+	}
+
+	return simplifyIdentity(e)
+}
+
+// simplifyLogical drops the bool literal sides of 'and' / 'or'. The other
+// side is kept alone only when known bool, as evaluation checks it is.
+func simplifyLogical(e *ExpressionBinary) Expression {
+	isAnd := e.Operator == _dslAnd
+
+	if literal, isLiteral := e.LefthandSide.(*ExpressionLiteral); isLiteral && literal.Value.kind == KindBool {
+		// 'false and x', 'true or x': x is never evaluated.
+		if literal.Value.boolean != isAnd {
+			return literalAt(e, literal.Value)
+		}
+
+		if kindOf(e.RighthandSide) == KindBool {
+			return e.RighthandSide
+		}
+
+		return e
+	}
+
+	if literal, isLiteral := e.RighthandSide.(*ExpressionLiteral); isLiteral && literal.Value.kind == KindBool {
+		if literal.Value.boolean == isAnd && kindOf(e.LefthandSide) == KindBool {
+			return e.LefthandSide
+		}
+	}
+
+	return e
+}
+
+// group gathers the constants of two nested multiplicative or additive
+// operations, e.g. '(x * 100) / 100' to 'x * (100 / 100)' before folding.
+// Divisions by zero are kept to fail as before, so are factors whose
+// grouping could overflow or underflow where the operations did not.
+func group(e *ExpressionBinary) (*ExpressionBinary, bool) {
+	inner, isBinary := e.LefthandSide.(*ExpressionBinary)
+	if !isBinary {
+		return nil, false
+	}
+
+	a, isNumberInner := literalNumber(inner.RighthandSide)
+	b, isNumberOuter := literalNumber(e.RighthandSide)
+
+	if !isNumberInner || !isNumberOuter {
+		return nil, false
+	}
+
+	var (
+		operator string
+		constant float64
+	)
+
+	switch inner.Operator + e.Operator {
+	case "**":
+		operator, constant = "*", a*b
+	case "*/":
+		if b == 0 {
+			return nil, false
+		}
+
+		operator, constant = "*", a/b
+	case "/*":
+		if a == 0 {
+			return nil, false
+		}
+
+		operator, constant = "*", b/a
+	case "//":
+		if a == 0 || b == 0 {
+			return nil, false
+		}
+
+		operator, constant = "/", a*b
+
+	case "++":
+		operator, constant = "+", a+b
+	case "+-":
+		operator, constant = "+", a-b
+	case "-+":
+		operator, constant = "+", b-a
+	case "--":
+		operator, constant = "-", a+b
+
+	default:
+		return nil, false
+	}
+
+	if math.IsInf(constant, 0) || math.IsNaN(constant) {
+		return nil, false
+	}
+
+	if operator == "*" || operator == "/" {
+		if !isModerate(a) || !isModerate(b) || !isModerate(constant) {
+			return nil, false
+		}
+	}
+
+	result := *e
+	result.Operator = operator
+	result.LefthandSide = inner.LefthandSide
+	result.RighthandSide = literalAt(e.RighthandSide, numberValue(constant))
+
+	return &result, true
+}
+
+// isModerate tells whether a factor is neither zero nor within 2^256 of the
+// float limits. Grouping such factors overflows and underflows the rows
+// the operations did one after the other, unless the value itself is that
+// close to the limits: folding 1e-200 * 1e-200 would give 0 instead.
+func isModerate(factor float64) bool {
+	if factor == 0 || math.IsInf(factor, 0) || math.IsNaN(factor) {
+		return false
+	}
+
+	_, exponent := math.Frexp(factor)
+
+	return exponent > -256 && exponent < 256
+}
+
+// simplifyIdentity drops the neutral constant of an arithmetic operation.
+// The other side is kept under a unary '+' unless known to be a number,
+// failing the kinds the operation failed.
+func simplifyIdentity(e *ExpressionBinary) Expression {
+	var operand Expression
+
+	right, isNumberRight := literalNumber(e.RighthandSide)
+	left, isNumberLeft := literalNumber(e.LefthandSide)
+
+	switch {
+	case isNumberRight && right == 0 && (e.Operator == "+" || e.Operator == "-"):
+		operand = e.LefthandSide
+	case isNumberRight && right == 1 && (e.Operator == "*" || e.Operator == "/"):
+		operand = e.LefthandSide
+	case isNumberLeft && left == 0 && e.Operator == "+":
+		operand = e.RighthandSide
+	case isNumberLeft && left == 1 && e.Operator == "*":
+		operand = e.RighthandSide
+
+	default:
+		return e
+	}
+
+	if kindOf(operand) == KindNumber {
+		return operand
+	}
+
+	return &ExpressionUnary{
+		Span:     e.Span,
+		Operator: "+",
+		Operand:  operand,
+	}
+}
@@ -0,0 +1,129 @@
+package dslalert
+
+import (
+	"errors"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// Program is an alert configuration compiled for evaluation: validated,
+// rules sorted by level once, conditions optimized and compiled.
+//
+// A Program is immutable and safe for concurrent use by any number of
+// goroutines, host functions permitting. It holds its own copy of the
+// configuration, later changes to the one compiled do not affect it.
+type Program struct {
+	criterias []*criteriaCompiled // configuration order
+
+	indexCriterias map[string]*criteriaCompiled // criteria name | criteria
+}
+
+// Compile validates the configuration and compiles it for evaluation.
+// Configurations with errors, found by Validate or marked by tolerant
+// parsing, are refused. The configuration is left unchanged.
+func Compile(configuration *AlertConfiguration) (*Program, error) {
+	if configuration == nil {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "Compile",
+				Issue: goerrors.ErrNilInput{
+					InputName: "configuration",
+				},
+			}
+	}
+
+	if errValidate := Validate(configuration).Err(); errValidate != nil {
+		return nil, errValidate
+	}
+
+	copied := Transform(
+		configuration,
+		func(expression Expression) Expression {
+			return expression
+		},
+	).(*AlertConfiguration)
+
+	result := Program{
+		criterias:      make([]*criteriaCompiled, 0, len(copied.Criterias)),
+		indexCriterias: make(map[string]*criteriaCompiled, len(copied.Criterias)),
+	}
+
+	for _, criteria := range copied.Criterias {
+		if errBroken := brokenNode(criteria); errBroken != nil {
+			return nil, errBroken
+		}
+
+		compiled := compileCriteria(criteria)
+
+		result.criterias = append(result.criterias, compiled)
+		result.indexCriterias[criteria.Name] = compiled
+	}
+
+	return &result, nil
+}
+
+// brokenNode reports the first node of the criteria tolerant parsing
+// could not read.
+func brokenNode(criteria *Criteria) error {
+	var result error
+
+	Inspect(
+		criteria,
+		func(node Node) bool {
+			if result != nil {
+				return false
+			}
+
+			var isError bool
+
+			switch n := node.(type) {
+			case *Criteria:
+				isError = n.IsError
+			case *Monitor:
+				isError = n.IsError
+			case *Rule:
+				isError = n.IsError
+			}
+
+			if isError {
+				result = goerrors.ErrValidation{
+					Caller: "Compile",
+					Issue: goerrors.ErrInvalidInput{
+						InputName:  "configuration",
+						InputValue: node.Location().Start.String(),
+						Issue:      errors.New("holds a node tolerant parsing could not read"),
+					},
+				}
+			}
+
+			return true
+		},
+	)
+
+	return result
+}
+
+// CriteriaNames returns the names of the criteria in configuration order.
+func (p *Program) CriteriaNames() []string {
+	result := make([]string, len(p.criterias))
+
+	for ix, criteria := range p.criterias {
+		result[ix] = criteria.name
+	}
+
+	return result
+}
+
+// EvaluateCriteria evaluates the criteria with the given name over a CSV
+// dataset, with the results of the package EvaluateCriteria.
+func (p *Program) EvaluateCriteria(name string, dataset []string) (EvaluationResults, error) {
+	criteria, exists := p.indexCriterias[name]
+	if !exists {
+		return nil,
+			goerrors.ErrEntryNotFound{
+				Key: name,
+			}
+	}
+
+	return criteria.evaluate(dataset)
+}
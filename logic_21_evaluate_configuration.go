@@ -0,0 +1,143 @@
+package dslalert
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// ParamsEvaluate configures EvaluateConfigurationWithParams.
+type ParamsEvaluate struct {
+	// Workers bounds the criteria evaluated at the same time,
+	// defaults to GOMAXPROCS.
+	Workers int
+}
+
+// EvaluateConfiguration compiles the configuration, see Compile, then
+// evaluates all its criteria over one CSV dataset read from source,
+// header first. Results are grouped by criteria in configuration order,
+// each with the rows the criteria could not evaluate fully, see
+// ConfigurationResults.Err.
+func EvaluateConfiguration(ctx context.Context, configuration *AlertConfiguration, source io.Reader) (ConfigurationResults, error) {
+	return EvaluateConfigurationWithParams(ctx, configuration, source, &ParamsEvaluate{})
+}
+
+func EvaluateConfigurationWithParams(ctx context.Context, configuration *AlertConfiguration, source io.Reader, params *ParamsEvaluate) (ConfigurationResults, error) {
+	program, errCompile := Compile(configuration)
+	if errCompile != nil {
+		return nil, errCompile
+	}
+
+	return program.EvaluateWithParams(ctx, source, params)
+}
+
+// Evaluate evaluates all the criteria of the program over one CSV dataset
+// read from source, header first, see EvaluateConfiguration.
+func (p *Program) Evaluate(ctx context.Context, source io.Reader) (ConfigurationResults, error) {
+	return p.EvaluateWithParams(ctx, source, &ParamsEvaluate{})
+}
+
+// EvaluateWithParams splits the dataset once and shares it with a bounded
+// pool of workers, each evaluating one criteria at a time. It returns
+// the error of the context when done before all criteria are evaluated.
+func (p *Program) EvaluateWithParams(ctx context.Context, source io.Reader, params *ParamsEvaluate) (ConfigurationResults, error) {
+	if source == nil {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "Evaluate",
+				Issue: goerrors.ErrNilInput{
+					InputName: "source",
+				},
+			}
+	}
+
+	lines, errRead := readLines(source)
+	if errRead != nil {
+		return nil, errRead
+	}
+
+	dataset, errDataset := newDatasetRows("Evaluate", lines)
+	if errDataset != nil {
+		return nil, errDataset
+	}
+
+	workers := params.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	workers = min(workers, len(p.criterias))
+
+	result := make(ConfigurationResults, len(p.criterias))
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	wg.Add(workers)
+
+	for range workers {
+		go func() {
+			defer wg.Done()
+
+			for ix := range jobs {
+				criteria := p.criterias[ix]
+
+				// only the context fails the evaluation, checked below.
+				results, errs, _ := criteria.evaluateRows(ctx, dataset)
+
+				result[ix] = CriteriaResults{
+					CriteriaName: criteria.name,
+					Results:      results,
+					Errors:       errs,
+				}
+			}
+		}()
+	}
+
+feed:
+	for ix := range p.criterias {
+		select {
+		case jobs <- ix:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+
+	close(jobs)
+
+	wg.Wait()
+
+	if errContext := ctx.Err(); errContext != nil {
+		return nil, errContext
+	}
+
+	return result, nil
+}
+
+// readLines reads the lines of a dataset, without their line endings.
+func readLines(source io.Reader) ([]string, error) {
+	var result []string
+
+	scanner := bufio.NewScanner(source)
+	scanner.Buffer(nil, 1<<20)
+
+	for scanner.Scan() {
+		result = append(result, scanner.Text())
+	}
+
+	if errScan := scanner.Err(); errScan != nil {
+		return nil,
+			fmt.Errorf(
+				"read dataset: %w",
+				errScan,
+			)
+	}
+
+	return result, nil
+}
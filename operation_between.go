@@ -0,0 +1,34 @@
+package dslalert
+
+import "fmt"
+
+// ExpressionBetween represents an inclusive range test
+// (e.g., 'value between 10 and 20', 'value not between 10 and 20').
+type ExpressionBetween struct {
+	Span
+
+	Operand Expression
+	Lower   Expression
+	Upper   Expression
+
+	IsNegated bool
+}
+
+func (e *ExpressionBetween) expressionNode() {}
+
+func (e *ExpressionBetween) String() string {
+	operator := _dslBetween
+	if e.IsNegated {
+		operator = _dslNot + " " + _dslBetween
+	}
+
+	return fmt.Sprintf(
+		"(%s %s %s %s %s)",
+
+		e.Operand.String(),
+		operator,
+		e.Lower.String(),
+		_dslAnd,
+		e.Upper.String(),
+	)
+}
@@ -1,21 +1,44 @@
 package dslalert
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+)
+
+// ExpressionBinary represents an infix operation (e.g., 'value > 5', 'a and b').
+type ExpressionBinary struct {
+	Span
 
-type expressionBinary struct {
 	Operator      string // (e.g., ">=", "<", "+", "==")
-	LefthandSide  expression
-	RighthandSide expression
+	LefthandSide  Expression
+	RighthandSide Expression
+
+	// Tolerance is the optional 'within' clause of '==' and '!='.
+	Tolerance Expression
+
+	pattern *regexp.Regexp // right side of 'matches', compiled at parse time
 }
 
-func (e *expressionBinary) interfaceMarker() {}
+func (e *ExpressionBinary) expressionNode() {}
+
+func (e *ExpressionBinary) String() string {
+	if e.Tolerance != nil {
+		return fmt.Sprintf(
+			"(%s %s %s %s %s)",
+
+			e.LefthandSide.String(),
+			e.Operator,
+			e.RighthandSide.String(),
+			_dslWithin,
+			e.Tolerance.String(),
+		)
+	}
 
-func (e *expressionBinary) string() string {
 	return fmt.Sprintf(
 		"(%s %s %s)",
 
-		e.LefthandSide.string(),
+		e.LefthandSide.String(),
 		e.Operator,
-		e.RighthandSide.string(),
+		e.RighthandSide.String(),
 	)
 }
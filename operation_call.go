@@ -0,0 +1,33 @@
+package dslalert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExpressionCall represents a function call (e.g., 'abs(value - 100)').
+type ExpressionCall struct {
+	Span
+
+	Name      string
+	Arguments []Expression
+
+	function *function // resolved at parse time
+}
+
+func (e *ExpressionCall) expressionNode() {}
+
+func (e *ExpressionCall) String() string {
+	arguments := make([]string, len(e.Arguments))
+
+	for ix, argument := range e.Arguments {
+		arguments[ix] = argument.String()
+	}
+
+	return fmt.Sprintf(
+		"%s(%s)",
+
+		e.Name,
+		strings.Join(arguments, ", "),
+	)
+}
@@ -0,0 +1,28 @@
+package dslalert
+
+import "fmt"
+
+// ExpressionConditional represents 'if condition then a else b'.
+// A 'case when ... end' expression is parsed into nested conditionals.
+type ExpressionConditional struct {
+	Span
+
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (e *ExpressionConditional) expressionNode() {}
+
+func (e *ExpressionConditional) String() string {
+	return fmt.Sprintf(
+		"(%s %s %s %s %s %s)",
+
+		_dslIf,
+		e.Condition.String(),
+		_dslThen,
+		e.Consequence.String(),
+		_dslElse,
+		e.Alternative.String(),
+	)
+}
@@ -0,0 +1,22 @@
+package dslalert
+
+import "strings"
+
+// ExpressionList represents a list literal (e.g., '[3, 5, 7]').
+type ExpressionList struct {
+	Span
+
+	Elements []Expression
+}
+
+func (e *ExpressionList) expressionNode() {}
+
+func (e *ExpressionList) String() string {
+	elements := make([]string, len(e.Elements))
+
+	for ix, element := range e.Elements {
+		elements[ix] = element.String()
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
@@ -1,19 +1,22 @@
 package dslalert
 
-type expressionLiteral struct {
-	value any    // use type assertion later (e.g., float64, string)
-	raw   string // original raw string representation
+// ExpressionLiteral represents a constant (e.g., '5', '"eu"', 'true', 'null').
+type ExpressionLiteral struct {
+	Span
+
+	Value Value
+	Raw   string // original raw string representation
 }
 
-func (e *expressionLiteral) interfaceMarker() {}
+func (e *ExpressionLiteral) expressionNode() {}
 
-func (e *expressionLiteral) string() string {
-	return e.raw
+func (e *ExpressionLiteral) String() string {
+	return e.Raw
 }
 
-func newliteral(value any, raw string) *expressionLiteral {
-	return &expressionLiteral{
-		value: value,
-		raw:   raw,
+func newLiteral(value Value, raw string) *ExpressionLiteral {
+	return &ExpressionLiteral{
+		Value: value,
+		Raw:   raw,
 	}
 }
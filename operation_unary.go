@@ -0,0 +1,32 @@
+package dslalert
+
+import "fmt"
+
+// ExpressionUnary represents a prefix operation (e.g., '-value', 'not value > 5').
+type ExpressionUnary struct {
+	Span
+
+	Operator string // (e.g., "-", "+", "!", "not")
+	Operand  Expression
+}
+
+func (e *ExpressionUnary) expressionNode() {}
+
+func (e *ExpressionUnary) String() string {
+	// keyword operators need a separator, symbols stick to the operand.
+	if e.Operator == _dslNot {
+		return fmt.Sprintf(
+			"(%s %s)",
+
+			e.Operator,
+			e.Operand.String(),
+		)
+	}
+
+	return fmt.Sprintf(
+		"(%s%s)",
+
+		e.Operator,
+		e.Operand.String(),
+	)
+}
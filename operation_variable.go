@@ -1,18 +1,20 @@
 package dslalert
 
-// expressionVariable represents a variable name (e.g., 'value').
-type expressionVariable struct {
-	name string
+// ExpressionVariable represents a variable name (e.g., 'value').
+type ExpressionVariable struct {
+	Span
+
+	Name string
 }
 
-func (e *expressionVariable) interfaceMarker() {}
+func (e *ExpressionVariable) expressionNode() {}
 
-func (e *expressionVariable) string() string {
-	return e.name
+func (e *ExpressionVariable) String() string {
+	return e.Name
 }
 
-func newVariable(name string) *expressionVariable {
-	return &expressionVariable{
-		name: name,
+func newVariable(name string) *ExpressionVariable {
+	return &ExpressionVariable{
+		Name: name,
 	}
 }
@@ -48,14 +48,14 @@ func TestAlertParsing(t *testing.T) {
 				regexp.MustCompile(
 					`(?i)(value|5)`,
 				),
-				rule1.Condition.string(),
+				rule1.Condition.String(),
 			)
 			rule2 := ast.Criterias[0].Monitors[0].Rules[1]
 			require.Regexp(t,
 				regexp.MustCompile(
 					`(?i)(value|10)`,
 				),
-				rule2.Condition.string(),
+				rule2.Condition.String(),
 			)
 		},
 	)
@@ -117,14 +117,14 @@ func TestAlertParsing(t *testing.T) {
 				regexp.MustCompile(
 					`(?i).*value.*5.*`,
 				),
-				criteria1Rule1.Condition.string(),
+				criteria1Rule1.Condition.String(),
 			)
 			criteria1Rule2 := ast.Criterias[0].Monitors[0].Rules[1]
 			require.Regexp(t,
 				regexp.MustCompile(
 					`(?i).*value.*10.*`,
 				),
-				criteria1Rule2.Condition.string(),
+				criteria1Rule2.Condition.String(),
 			)
 
 			criteria2Rule1 := ast.Criterias[1].Monitors[0].Rules[0]
@@ -132,15 +132,91 @@ func TestAlertParsing(t *testing.T) {
 				regexp.MustCompile(
 					`(?i).*value.*7.*`,
 				),
-				criteria2Rule1.Condition.string(),
+				criteria2Rule1.Condition.String(),
 			)
 			criteria2Rule2 := ast.Criterias[1].Monitors[0].Rules[1]
 			require.Regexp(t,
 				regexp.MustCompile(
 					`(?i).*value.*9.*`,
 				),
-				criteria2Rule2.Condition.string(),
+				criteria2Rule2.Condition.String(),
 			)
 		},
 	)
+
+	t.Run(
+		"3. comments and doc comments",
+		func(t *testing.T) {
+			inputValid := `
+			// orders volume checks.
+			// owned by the sales team.
+			criteria "c1" {
+				/* not a doc: separated by a blank line */
+
+				// spikes in orders.
+				monitor "column_orders" {
+					level 1 when value > 5; // warning only
+					// page on-call.
+					level 2 when value > 10;
+				}
+			} // end c1
+
+			/* returns
+			   checks */
+			criteria "c2" {
+				monitor "column_returns" {
+					level 1 when value > 7;
+				}
+			}
+			`
+			reader := strings.NewReader(inputValid)
+
+			ast, errs := Parse(reader)
+
+			require.Empty(t, errs, "should have no parsing errors")
+			require.Len(t,
+				ast.Criterias,
+				2,
+			)
+
+			criteria1 := ast.Criterias[0]
+			require.Equal(t,
+				"orders volume checks.\nowned by the sales team.",
+				criteria1.Doc,
+			)
+			require.Equal(t,
+				"end c1",
+				criteria1.Comment,
+			)
+			require.Equal(t,
+				"spikes in orders.",
+				criteria1.Monitors[0].Doc,
+			)
+			require.Empty(t,
+				criteria1.Monitors[0].Rules[0].Doc,
+			)
+			require.Equal(t,
+				"warning only",
+				criteria1.Monitors[0].Rules[0].Comment,
+			)
+			require.Equal(t,
+				"page on-call.",
+				criteria1.Monitors[0].Rules[1].Doc,
+			)
+			require.Equal(t,
+				"returns\nchecks",
+				ast.Criterias[1].Doc,
+			)
+
+			results, errEvaluate := EvaluateCriteria(
+				criteria1,
+				[]string{"column_orders", "11"},
+			)
+			require.NoError(t, errEvaluate)
+			require.Len(t, results, 1)
+			require.Equal(t, "page on-call.", results[0].RuleDoc)
+			require.Contains(t, results.String(), "page on-call.")
+			require.Contains(t, results.Message(), "orders volume checks.")
+		},
+	)
 }
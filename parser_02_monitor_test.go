@@ -21,12 +21,12 @@ func TestMonitorParsing(t *testing.T) {
 
 			_ = p.parseMonitor()
 
-			require.NotNil(t, p.errors, "should reject empty monitor")
+			require.NotNil(t, p.diagnostics, "should reject empty monitor")
 			require.Regexp(t,
 				regexp.MustCompile(`(?i)(must contain at least one level rule|empty monitor|expected level rule)`),
-				p.errors[0],
+				p.diagnostics[0].Message,
 				"should reject empty monitor blocks\nGot: %q",
-				p.errors[0],
+				p.diagnostics[0].Message,
 			)
 		},
 	)
@@ -45,14 +45,14 @@ func TestMonitorParsing(t *testing.T) {
 
 			_ = p.parseMonitor()
 
-			require.NotEmpty(t, p.errors, "should report error")
+			require.NotEmpty(t, p.diagnostics, "should report error")
 			require.Regexp(t,
 				regexp.MustCompile(
 					`expected [}]|token 11|not properly closed|missing closing brace`,
 				),
-				strings.ToLower(p.errors[0]),
+				strings.ToLower(p.diagnostics[0].Message),
 				"should detect unclosed block\nGot: %q",
-				p.errors[0],
+				p.diagnostics[0].Message,
 			)
 		},
 	)
@@ -71,12 +71,12 @@ func TestMonitorParsing(t *testing.T) {
 
 			_ = p.parseMonitor()
 
-			require.NotEmpty(t, p.errors, "should report error")
+			require.NotEmpty(t, p.diagnostics, "should report error")
 			require.Regexp(t,
 				regexp.MustCompile(`(?i)(expected\s+string|missing\s+monitor\s+name|token\s+8)`),
-				p.errors[0],
+				p.diagnostics[0].Message,
 				"should complain about missing name\nGot: %q",
-				p.errors[0],
+				p.diagnostics[0].Message,
 			)
 		},
 	)
@@ -97,7 +97,7 @@ func TestMonitorParsing(t *testing.T) {
 
 			monitor := p.parseMonitor()
 
-			require.Empty(t, p.errors)
+			require.Empty(t, p.diagnostics)
 			require.Equal(t, "orders", monitor.ColumnName)
 			require.Len(t, monitor.Rules, 1, "should parse the rule")
 		},
@@ -122,11 +122,11 @@ func TestMonitorParsing(t *testing.T) {
 
 			monitor := p.parseMonitor()
 
-			require.Empty(t, p.errors)
+			require.Empty(t, p.diagnostics)
 			require.Equal(t, "orders", monitor.ColumnName)
 			require.Len(t, monitor.Rules, 2, "should parse both rules")
 			require.Equal(t, 1, monitor.Rules[0].Level)
-			require.Contains(t, monitor.Rules[0].Condition.string(), "value > 5")
+			require.Contains(t, monitor.Rules[0].Condition.String(), "value > 5")
 		},
 	)
 }
@@ -22,8 +22,8 @@ func TestRuleParsing(t *testing.T) {
 
 			_ = p.parseRule()
 
-			require.NotNil(t, p.errors, "should report missing semicolon")
-			errorMsg := strings.ToLower(p.errors[0])
+			require.NotNil(t, p.diagnostics, "should report missing semicolon")
+			errorMsg := strings.ToLower(p.diagnostics[0].Message)
 			require.True(t,
 				strings.Contains(errorMsg, "expected ;") ||
 					strings.Contains(errorMsg, "token 13"), // tokenSemicolon
@@ -45,11 +45,11 @@ func TestRuleParsing(t *testing.T) {
 
 			_ = p.parseRule()
 
-			require.NotNil(t, p.errors, "should report invalid level")
-			errorMsg := strings.ToLower(p.errors[0])
+			require.NotNil(t, p.diagnostics, "should report invalid level")
+			errorMsg := strings.ToLower(p.diagnostics[0].Message)
 			require.True(t,
 				strings.Contains(errorMsg, "invalid level number") ||
-					strings.Contains(errorMsg, "expected token 9"), // tokenNumber
+					strings.Contains(errorMsg, "expected number"),
 				"error should indicate invalid level (got: %s)", errorMsg)
 		},
 	)
@@ -68,9 +68,9 @@ func TestRuleParsing(t *testing.T) {
 
 			rule := p.parseRule()
 
-			require.Nil(t, p.errors, "should have no errors")
+			require.Nil(t, p.diagnostics, "should have no errors")
 			require.Equal(t, 1, rule.Level, "level should be 1")
-			require.Contains(t, rule.Condition.string(), "value > 5", "condition mismatch")
+			require.Contains(t, rule.Condition.String(), "value > 5", "condition mismatch")
 		},
 	)
 }
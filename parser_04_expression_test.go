@@ -8,7 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func parseExpr(input string) expression {
+func parseExpr(input string) Expression {
 	p := newParser(
 		&paramsNewParser{
 			Lexer:       newLexer(strings.NewReader(input)),
@@ -19,12 +19,12 @@ func parseExpr(input string) expression {
 	expr := p.parseExpression(0)
 
 	// Verify we consumed full input
-	if p.tokenCurrent.kind != tokenEOF && len(p.errors) == 0 {
-		p.errorf("unexpected trailing tokens")
+	if p.tokenCurrent.kind != tokenEOF && len(p.diagnostics) == 0 {
+		p.errorf(CodeUnexpectedToken, "unexpected trailing tokens")
 	}
 
-	if len(p.errors) > 0 {
-		panic(fmt.Sprintf("parse error: %v", p.errors))
+	if len(p.diagnostics) > 0 {
+		panic(fmt.Sprintf("parse error: %v", p.diagnostics))
 	}
 	return expr
 }
@@ -35,7 +35,7 @@ func TestOperatorPrecedence(t *testing.T) {
 		func(t *testing.T) {
 			input := "1 + 2 * 3"
 			expr := parseExpr(input) // Helper to parse single expression
-			require.Equal(t, "(1 + (2 * 3))", expr.string())
+			require.Equal(t, "(1 + (2 * 3))", expr.String())
 		},
 	)
 
@@ -44,7 +44,78 @@ func TestOperatorPrecedence(t *testing.T) {
 		func(t *testing.T) {
 			input := "value > threshold + 5"
 			expr := parseExpr(input)
-			require.Equal(t, "(value > (threshold + 5))", expr.string())
+			require.Equal(t, "(value > (threshold + 5))", expr.String())
+		},
+	)
+
+	t.Run(
+		"logical connectives below comparisons",
+		func(t *testing.T) {
+			input := "value > 5 and value < 50 or value >= 100"
+			expr := parseExpr(input)
+			require.Equal(t,
+				"(((value > 5) and (value < 50)) or (value >= 100))",
+				expr.String(),
+			)
+		},
+	)
+
+	t.Run(
+		"not binds looser than comparison",
+		func(t *testing.T) {
+			input := "not value > 5 and value < 50"
+			expr := parseExpr(input)
+			require.Equal(t,
+				"((not (value > 5)) and (value < 50))",
+				expr.String(),
+			)
+		},
+	)
+}
+
+func TestGroupingAndUnary(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"(value - 10) * 2 > 5", "(((value - 10) * 2) > 5)"},
+		{"value > -3", "(value > (-3))"},
+		{"-value * 2", "((-value) * 2)"},
+		{"+value - -2", "((+value) - (-2))"},
+		{"!(value > 5)", "(!(value > 5))"},
+		{"not (value > 5 or value < 1)", "(not ((value > 5) or (value < 1)))"},
+		{"value == 5 within 0.01", "(value == 5 within 0.01)"},
+		{"value != 5 and value == 1 + 2", "((value != 5) and (value == (1 + 2)))"},
+		{"value == 2 + 3 within 0.5 * 2", "(value == (2 + 3) within (0.5 * 2))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.input,
+			func(t *testing.T) {
+				expr := parseExpr(tt.input)
+				require.Equal(t, tt.want, expr.String())
+
+				// string output must parse back to the same tree.
+				require.Equal(t,
+					tt.want,
+					parseExpr(expr.String()).String(),
+				)
+			},
+		)
+	}
+
+	t.Run(
+		"error - unclosed parenthesis",
+		func(t *testing.T) {
+			p := newParser(
+				&paramsNewParser{
+					Lexer: newLexer(strings.NewReader("(value > 5")),
+				},
+			)
+
+			require.Nil(t, p.parseExpression(0))
+			require.NotEmpty(t, p.diagnostics)
 		},
 	)
 }
@@ -64,11 +135,11 @@ func TestExpressionParsing(t *testing.T) {
 
 			expr := p.parseExpression(0)
 
-			require.IsType(t, &expressionBinary{}, expr)
-			binExpr := expr.(*expressionBinary)
+			require.IsType(t, &ExpressionBinary{}, expr)
+			binExpr := expr.(*ExpressionBinary)
 			require.Equal(t,
 				"value",
-				binExpr.LefthandSide.string(),
+				binExpr.LefthandSide.String(),
 			)
 			require.Equal(t,
 				">",
@@ -76,8 +147,31 @@ func TestExpressionParsing(t *testing.T) {
 			)
 			require.Equal(t,
 				"5",
-				binExpr.RighthandSide.string(),
+				binExpr.RighthandSide.String(),
 			)
 		},
 	)
 }
+
+func TestTrailingComma(t *testing.T) {
+	for ix, tt := range []struct {
+		condition string
+		message   string
+	}{
+		{"abs(value,) > 1", "expected an argument after ','"},
+		{"max(value, 1,) > 1", "expected an argument after ','"},
+		{"value in [1, 2,]", "expected an element after ','"},
+	} {
+		t.Run(
+			fmt.Sprintf("%d. %s", ix+1, tt.condition),
+			func(t *testing.T) {
+				_, diagnostics := Parse(
+					strings.NewReader(`criteria "c1" { monitor "a" { level 1 when ` + tt.condition + "; } }"),
+				)
+				require.NotEmpty(t, diagnostics)
+				require.ErrorIs(t, diagnostics[0], CodeUnexpectedToken)
+				require.Contains(t, diagnostics[0].Message, tt.message)
+			},
+		)
+	}
+}
@@ -0,0 +1,132 @@
+package dslalert
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	t.Run(
+		"1. span and expected tokens",
+		func(t *testing.T) {
+			input := `criteria "c1" {
+	monitor "orders" {
+		level 1 when value > 5
+	}
+}`
+
+			ast, diagnostics := Parse(strings.NewReader(input))
+			require.Nil(t, ast)
+			require.NotEmpty(t, diagnostics)
+
+			first := diagnostics[0]
+			require.Equal(t, SeverityError, first.Severity)
+			require.Equal(t, CodeUnexpectedToken, first.Code)
+			require.Equal(t, 4, first.Start.Line)
+			require.Equal(t, 2, first.Start.Column)
+			require.Equal(t, 3, first.End.Column)
+			require.Len(t, first.Expected, 1)
+			require.NotContains(t, first.Message, "Caller")
+		},
+	)
+
+	t.Run(
+		"2. errors.Is and errors.As",
+		func(t *testing.T) {
+			_, diagnostics := Parse(strings.NewReader(`criteria "c1" { monitor "a" { level 1 when value > @; } }`))
+
+			err := diagnostics.Err()
+			require.Error(t, err)
+			require.ErrorIs(t, err, CodeInvalidCharacter)
+			require.NotErrorIs(t, err, CodeEmptyInput)
+
+			var diagnostic Diagnostic
+			require.True(t, errors.As(err, &diagnostic))
+			require.Equal(t, CodeInvalidCharacter, diagnostic.Code)
+			require.Equal(t, 52, diagnostic.Start.Column)
+		},
+	)
+
+	t.Run(
+		"3. warnings alone are not an error",
+		func(t *testing.T) {
+			diagnostics := Diagnostics{
+				{
+					Severity: SeverityWarning,
+					Code:     CodeEmptyBlock,
+				},
+			}
+
+			require.NoError(t, diagnostics.Err())
+			require.False(t, diagnostics.HasErrors())
+		},
+	)
+}
+
+func TestRenderDiagnostics(t *testing.T) {
+	t.Run(
+		"1. snippet with caret",
+		func(t *testing.T) {
+			input := `criteria "c1" {
+	monitor "orders" {
+		level 1 when value > 5
+	}
+}`
+
+			_, diagnostics := Parse(strings.NewReader(input))
+			require.NotEmpty(t, diagnostics)
+
+			require.Equal(t,
+				"error[DSL004]: expected ;, got }\n"+
+					" --> <input>:4:2\n"+
+					"  |\n"+
+					"4 | \t}\n"+
+					"  | \t^\n"+
+					"  = expected: ;\n",
+				diagnostics[0].Render(input),
+			)
+		},
+	)
+
+	t.Run(
+		"2. underline spans the token",
+		func(t *testing.T) {
+			input := `level 1 when value > 5 or value matches "[0-9";`
+
+			_, diagnostics := Parse(strings.NewReader(`criteria "c1" { monitor "a" { ` + input + ` } }`))
+			require.NotEmpty(t, diagnostics)
+			require.Contains(t,
+				diagnostics.Render(`criteria "c1" { monitor "a" { `+input+` } }`),
+				strings.Repeat(" ", 69)+`^^^^^^`,
+			)
+		},
+	)
+
+	for misspelled, keyword := range map[string]string{
+		`criteria "c1" { monitr "orders" { level 1 when value > 5; } }`: _dslMonitor,
+		`criteria "c1" { monitor "orders" { level 1 whn value > 5; } }`: _dslWhen,
+	} {
+		t.Run(
+			"3. did you mean "+keyword,
+			func(t *testing.T) {
+				_, diagnostics := Parse(strings.NewReader(misspelled))
+				require.NotEmpty(t, diagnostics)
+				require.Equal(t, "did you mean `"+keyword+"`?", diagnostics[0].Hint)
+				require.Contains(t, diagnostics.Render(misspelled), "= hint: did you mean `"+keyword+"`?")
+			},
+		)
+	}
+
+	t.Run(
+		"4. token kinds by spelling",
+		func(t *testing.T) {
+			require.Equal(t, ";", tokenSemicolon.String())
+			require.Equal(t, "}", tokenRightBrace.String())
+			require.Equal(t, "level", tokenLevel.String())
+			require.Equal(t, "", suggestKeyword("orders"))
+		},
+	)
+}
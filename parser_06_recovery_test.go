@@ -0,0 +1,184 @@
+package dslalert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTolerantParsing(t *testing.T) {
+	input := `criteria "c1" {
+	monitor "orders" {
+		level 1 when value > ;
+		level 2 when value > 5;
+		level abc when value > 7;
+		level 3 when value > 9
+	}
+
+	monitr "refunds" {
+		level 1 when value > 1;
+	}
+
+	monitor "latency" {
+		level 1 when value > 100;
+	}
+}
+
+criteria "c2" {
+}
+
+criteria "c3" {
+	monitor "errors" {
+		level 1 when value > 0;
+	}
+}`
+
+	t.Run(
+		"1. strict parse reports every problem",
+		func(t *testing.T) {
+			ast, diagnostics := Parse(strings.NewReader(input))
+			require.Nil(t, ast)
+
+			lines := make([]int, 0, len(diagnostics))
+
+			for _, diagnostic := range diagnostics {
+				lines = append(lines, diagnostic.Start.Line)
+			}
+
+			require.Contains(t, lines, 3)  // missing operand
+			require.Contains(t, lines, 5)  // invalid level
+			require.Contains(t, lines, 7)  // missing ';'
+			require.Contains(t, lines, 9)  // misspelled monitor
+			require.Contains(t, lines, 19) // empty criteria
+		},
+	)
+
+	t.Run(
+		"2. tolerant parse keeps the well-formed nodes",
+		func(t *testing.T) {
+			ast, diagnostics := ParseWithParams(
+				strings.NewReader(input),
+				&ParamsParse{
+					IsTolerant: true,
+				},
+			)
+			require.True(t, diagnostics.HasErrors())
+			require.NotNil(t, ast)
+			require.Len(t, ast.Criterias, 3)
+
+			c1 := ast.Criterias[0]
+			require.True(t, c1.IsError, "misspelled monitor skipped")
+			require.Len(t, c1.Monitors, 2)
+
+			orders := c1.Monitors[0]
+			require.Equal(t, "orders", orders.ColumnName)
+			require.Len(t, orders.Rules, 4)
+			require.True(t, orders.Rules[0].IsError)
+			require.False(t, orders.Rules[1].IsError)
+			require.Equal(t, "(value > 5)", orders.Rules[1].Condition.String())
+			require.True(t, orders.Rules[2].IsError)
+			require.True(t, orders.Rules[3].IsError)
+
+			require.Equal(t, "latency", c1.Monitors[1].ColumnName)
+			require.False(t, c1.Monitors[1].IsError)
+
+			require.Equal(t, "c2", ast.Criterias[1].Name)
+			require.True(t, ast.Criterias[1].IsError)
+
+			require.Equal(t, "c3", ast.Criterias[2].Name)
+			require.False(t, ast.Criterias[2].IsError)
+			require.Len(t, ast.Criterias[2].Monitors, 1)
+		},
+	)
+
+	t.Run(
+		"3. error nodes are not evaluated",
+		func(t *testing.T) {
+			ast, _ := ParseWithParams(
+				strings.NewReader(input),
+				&ParamsParse{
+					IsTolerant: true,
+				},
+			)
+
+			results, errEvaluate := EvaluateCriteria(
+				ast.Criterias[0],
+				[]string{
+					"orders,latency",
+					"8,50",
+				},
+			)
+			require.NoError(t, errEvaluate)
+			require.Len(t, results, 1)
+			require.Equal(t, 2, results[0].RuleLevel)
+		},
+	)
+
+	t.Run(
+		"4. an unexpected character is reported once and skipped",
+		func(t *testing.T) {
+			ast, diagnostics := ParseWithParams(
+				strings.NewReader(`criteria "c1" {
+	monitor "orders" {
+		level 1 when value > 5 @;
+	}
+}
+
+criteria "c2" {
+	monitor "refunds" {
+		level 1 when value > 1;
+	}
+}`),
+				&ParamsParse{
+					IsTolerant: true,
+				},
+			)
+			require.Len(t, diagnostics, 1)
+			require.Equal(t, CodeInvalidCharacter, diagnostics[0].Code)
+			require.Equal(t, 3, diagnostics[0].Start.Line)
+
+			require.NotNil(t, ast)
+			require.Len(t, ast.Criterias, 2)
+			require.Equal(t, "(value > 5)", ast.Criterias[0].Monitors[0].Rules[0].Condition.String())
+
+			c2 := ast.Criterias[1]
+			require.Equal(t, "c2", c2.Name)
+			require.False(t, c2.IsError)
+			require.Len(t, c2.Monitors, 1)
+			require.Equal(t, "(value > 1)", c2.Monitors[0].Rules[0].Condition.String())
+		},
+	)
+
+	t.Run(
+		"5. a body skipped in part marks its node",
+		func(t *testing.T) {
+			ast, diagnostics := ParseWithParams(
+				strings.NewReader(`criteria "a" {
+	monitr "x" {
+		level 1 when value > 1;
+	}
+}
+
+criteria "b" {
+	monitor "y" {
+		lvl 1 when value > 1;
+		level 2 when value > 2;
+	}
+}`),
+				&ParamsParse{
+					IsTolerant: true,
+				},
+			)
+			require.True(t, diagnostics.HasErrors())
+			require.Len(t, ast.Criterias, 2)
+
+			require.True(t, ast.Criterias[0].IsError)
+			require.Empty(t, ast.Criterias[0].Monitors)
+
+			require.False(t, ast.Criterias[1].IsError)
+			require.True(t, ast.Criterias[1].Monitors[0].IsError)
+			require.Len(t, ast.Criterias[1].Monitors[0].Rules, 1)
+		},
+	)
+}
@@ -0,0 +1,187 @@
+package dslalert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	input := `criteria "c1" {
+	monitor "orders" {
+		level 1 when value > 5 and abs(value) < 100;
+		level 2 when value between 10 and 20;
+	}
+}`
+
+	ast, diagnostics := Parse(strings.NewReader(input))
+	require.Empty(t, diagnostics)
+
+	t.Run(
+		"1. spans",
+		func(t *testing.T) {
+			require.Equal(t, 1, ast.Start.Line)
+			require.Equal(t, 6, ast.End.Line)
+
+			monitor := ast.Criterias[0].Monitors[0]
+			require.Equal(t, 2, monitor.Start.Line)
+			require.Equal(t, 2, monitor.Start.Column)
+			require.Equal(t, 5, monitor.End.Line)
+			require.Equal(t, 3, monitor.End.Column)
+
+			rule := monitor.Rules[0]
+			require.Equal(t, 3, rule.Start.Column)
+			require.Equal(t, 47, rule.End.Column) // after ';'
+
+			condition := rule.Condition.(*ExpressionBinary)
+			require.Equal(t, 16, condition.Start.Column)
+			require.Equal(t, 46, condition.End.Column)
+
+			call := condition.RighthandSide.(*ExpressionBinary).LefthandSide
+			require.Equal(t, "abs(value)", input[call.Location().Start.Offset:call.Location().End.Offset])
+		},
+	)
+
+	t.Run(
+		"2. inspect in source order",
+		func(t *testing.T) {
+			var visited []string
+
+			Inspect(
+				ast,
+				func(node Node) bool {
+					switch n := node.(type) {
+					case *Rule:
+						visited = append(visited, "rule")
+
+					case *ExpressionVariable:
+						visited = append(visited, n.Name)
+
+					case *ExpressionLiteral:
+						visited = append(visited, n.Raw)
+					}
+
+					return node != nil
+				},
+			)
+
+			require.Equal(t,
+				[]string{
+					"rule", "value", "5", "value", "100",
+					"rule", "value", "10", "20",
+				},
+				visited,
+			)
+		},
+	)
+
+	t.Run(
+		"3. inspect stops at false",
+		func(t *testing.T) {
+			var count int
+
+			Inspect(
+				ast,
+				func(node Node) bool {
+					if node != nil {
+						count++
+					}
+
+					_, isRule := node.(*Rule)
+
+					return !isRule
+				},
+			)
+
+			require.Equal(t, 5, count) // configuration, criteria, monitor, 2 rules
+		},
+	)
+
+	t.Run(
+		"4. transform",
+		func(t *testing.T) {
+			transformed := Transform(
+				ast,
+				func(expression Expression) Expression {
+					if variable, isVariable := expression.(*ExpressionVariable); isVariable && variable.Name == _dslValue {
+						return newVariable("amount")
+					}
+
+					return expression
+				},
+			).(*AlertConfiguration)
+
+			require.Equal(t,
+				"((amount > 5) and (abs(amount) < 100))",
+				transformed.Criterias[0].Monitors[0].Rules[0].Condition.String(),
+			)
+			require.Equal(t,
+				"((value > 5) and (abs(value) < 100))",
+				ast.Criterias[0].Monitors[0].Rules[0].Condition.String(),
+				"input left unchanged",
+			)
+
+			results, errEvaluate := EvaluateCriteria(
+				transformed.Criterias[0],
+				[]string{
+					"orders,amount",
+					"0,15",
+				},
+			)
+			require.NoError(t, errEvaluate)
+			require.Equal(t, 2, results.LevelMaximum())
+		},
+	)
+
+	t.Run(
+		"5. absent nodes are not visited",
+		func(t *testing.T) {
+			Inspect(
+				nil,
+				func(Node) bool {
+					require.Fail(t, "nil visited")
+
+					return true
+				},
+			)
+
+			rule := &Rule{
+				Level: 1,
+			}
+
+			var visited []Node
+
+			Inspect(
+				rule,
+				func(node Node) bool {
+					visited = append(visited, node)
+
+					return true
+				},
+			)
+			require.Equal(t, []Node{rule, nil}, visited)
+
+			require.NotPanics(
+				t,
+				func() {
+					Validate(
+						&AlertConfiguration{
+							Criterias: []*Criteria{
+								{
+									Name: "c1",
+									Monitors: []*Monitor{
+										{
+											ColumnName: "a",
+											Rules:      []*Rule{rule},
+										},
+									},
+								},
+							},
+						},
+					)
+				},
+			)
+		},
+	)
+}
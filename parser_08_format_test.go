@@ -0,0 +1,265 @@
+package dslalert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// dumpAST lists what makes two configurations equivalent,
+// conditions being printed fully parenthesized.
+func dumpAST(configuration *AlertConfiguration) []string {
+	var result []string
+
+	for _, criteria := range configuration.Criterias {
+		result = append(result, fmt.Sprintf("criteria %q detached=%q doc=%q opening=%q dangling=%q comment=%q", criteria.Name, criteria.Detached, criteria.Doc, criteria.CommentOpening, criteria.Dangling, criteria.Comment))
+
+		for _, monitor := range criteria.Monitors {
+			result = append(result, fmt.Sprintf("monitor %q detached=%q doc=%q opening=%q dangling=%q comment=%q", monitor.ColumnName, monitor.Detached, monitor.Doc, monitor.CommentOpening, monitor.Dangling, monitor.Comment))
+
+			for _, rule := range monitor.Rules {
+				result = append(result, fmt.Sprintf("level %d %s detached=%q doc=%q comment=%q", rule.Level, rule.Condition.String(), rule.Detached, rule.Doc, rule.Comment))
+			}
+		}
+	}
+
+	return append(result, fmt.Sprintf("dangling=%q", configuration.Dangling))
+}
+
+func TestFormat(t *testing.T) {
+	input := `// orders alerting
+// owned by team a
+criteria    "c1"{ // two monitors
+monitor "orders" {
+  /* spike
+     in orders */
+  level 1 when (value > 5) and ((value < 100));   // warning
+      level 2 when not (value > 5 and value < 10) or -(value + 1) * 2 > 3;
+  level 3 when value - (2 - 1) == 4 within (0.5 + 0.5);
+	}
+
+	monitor "region" { level 1 when region in ["eu", "us"] and not (region startswith "x"); } // region
+} // end c1
+criteria "c2" {
+	monitor "latency" { /* milliseconds,
+	p99 */
+		level 1 when (if value > 5 then 1 else 2) + 1 > 2;
+		level 2 when case when value > 9 then true else false end;
+		level 3 when value not between 1 + 1 and (if value > 0 then 3 else 4);
+		level 4 when abs(value - 100) > max(1, 2) and value matches ` + "`^[0-9]+$`" + `;
+	}
+}
+`
+
+	want := `// orders alerting
+// owned by team a
+criteria "c1" { // two monitors
+	monitor "orders" {
+		// spike
+		// in orders
+		level 1 when value > 5 and value < 100; // warning
+		level 2 when not (value > 5 and value < 10) or -(value + 1) * 2 > 3;
+		level 3 when value - (2 - 1) == 4 within 0.5 + 0.5;
+	}
+
+	monitor "region" {
+		level 1 when region in ["eu", "us"] and not region startswith "x";
+	} // region
+} // end c1
+
+criteria "c2" {
+	monitor "latency" { /* milliseconds,
+p99 */
+		level 1 when (if value > 5 then 1 else 2) + 1 > 2;
+		level 2 when if value > 9 then true else false;
+		level 3 when value not between 1 + 1 and (if value > 0 then 3 else 4);
+		level 4 when abs(value - 100) > max(1, 2) and value matches "^[0-9]+$";
+	}
+}
+`
+
+	ast, diagnostics := Parse(strings.NewReader(input))
+	require.Empty(t, diagnostics)
+
+	t.Run(
+		"1. canonical text",
+		func(t *testing.T) {
+			require.Equal(t, want, Format(ast))
+		},
+	)
+
+	t.Run(
+		"2. parsing the formatted text gives an equivalent AST",
+		func(t *testing.T) {
+			formatted := Format(ast)
+
+			reparsed, diagnosticsReparse := Parse(strings.NewReader(formatted))
+			require.Empty(t, diagnosticsReparse)
+			require.Equal(t, dumpAST(ast), dumpAST(reparsed))
+			require.Equal(t, formatted, Format(reparsed), "formatting is idempotent")
+		},
+	)
+
+	for _, condition := range []string{
+		`(a - b) - c`,
+		`a - (b - c)`,
+		`-(-value)`,
+		`not not value`,
+		`(not value) == false`,
+		`value == (not false)`,
+		`(value == 1 within 0.1) == true`,
+		`value == (1 == 1 within 0.1)`,
+		`(value between 1 and 2) between false and true`,
+		`[if value then 1 else 2, 3]`,
+		`if if a then b else c then d else e`,
+		`a and not b and c`,
+		`a and (not b or c)`,
+		`a or not b and c`,
+		`a * (not b) + c`,
+		`a == (not b and c)`,
+	} {
+		t.Run(
+			"3. round trip "+condition,
+			func(t *testing.T) {
+				expression := parseExpr(condition)
+
+				require.Equal(t,
+					expression.String(),
+					parseExpr(FormatExpression(expression)).String(),
+					"formatted: %s", FormatExpression(expression),
+				)
+			},
+		)
+	}
+}
+
+func TestFormatComments(t *testing.T) {
+	input := `// file header
+
+// orders alerting
+criteria "c1" { // opening c1
+	// about the monitors
+
+	monitor "orders" { // opening orders
+		level 1 when value > 5;
+		// level 2 kept apart
+
+		level 2 when value > 10;
+		// to do: level 3
+	}
+	// between the monitors
+
+	monitor "region" {
+		level 1 when region == "eu";
+	}
+
+	// end of c1, first group
+
+	/* end of c1,
+	   second group */
+}
+
+// end of input
+`
+
+	want := `// file header
+
+// orders alerting
+criteria "c1" { // opening c1
+	// about the monitors
+
+	monitor "orders" { // opening orders
+		level 1 when value > 5;
+		// level 2 kept apart
+
+		level 2 when value > 10;
+		// to do: level 3
+	}
+
+	// between the monitors
+
+	monitor "region" {
+		level 1 when region == "eu";
+	}
+
+	// end of c1, first group
+
+	// end of c1,
+	// second group
+}
+
+// end of input
+`
+
+	ast, diagnostics := Parse(strings.NewReader(input))
+	require.Empty(t, diagnostics)
+
+	t.Run(
+		"1. comments are kept",
+		func(t *testing.T) {
+			require.Equal(t, want, Format(ast))
+		},
+	)
+
+	t.Run(
+		"2. parsing the formatted text gives an equivalent AST",
+		func(t *testing.T) {
+			formatted := Format(ast)
+
+			reparsed, diagnosticsReparse := Parse(strings.NewReader(formatted))
+			require.Empty(t, diagnosticsReparse)
+			require.Equal(t, dumpAST(ast), dumpAST(reparsed))
+			require.Equal(t, formatted, Format(reparsed), "formatting is idempotent")
+		},
+	)
+
+	t.Run(
+		"3. only comments",
+		func(t *testing.T) {
+			ast, diagnostics := Parse(strings.NewReader("// nothing yet\n"))
+			require.Empty(t, diagnostics)
+
+			require.Equal(t, "// nothing yet\n", Format(ast))
+		},
+	)
+}
+
+func TestFormatSyntaxOnly(t *testing.T) {
+	input := `criteria "c1" { monitor "amount" {
+	level 1 when geo_distance(value, 5, "km") > 10;
+	level 1 when value > 5;
+} }`
+
+	_, diagnostics := Parse(strings.NewReader(input))
+	require.True(t, diagnostics.HasErrors())
+
+	ast, diagnostics := ParseWithParams(
+		strings.NewReader(input),
+		&ParamsParse{
+			IsSyntaxOnly: true,
+		},
+	)
+	require.Empty(t, diagnostics)
+
+	require.Equal(
+		t,
+		`criteria "c1" {
+	monitor "amount" {
+		level 1 when geo_distance(value, 5, "km") > 10;
+		level 1 when value > 5;
+	}
+}
+`,
+		Format(ast),
+	)
+
+	_, diagnostics = ParseWithParams(
+		strings.NewReader(`criteria "c1" { monitor "amount" { level 1 when value > ; } }`),
+		&ParamsParse{
+			IsSyntaxOnly: true,
+		},
+	)
+	require.True(t, diagnostics.HasErrors(), "syntax errors are still reported")
+}
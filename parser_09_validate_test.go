@@ -0,0 +1,153 @@
+package dslalert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		code  Code
+		line  int
+	}{
+		{
+			name: "duplicate criteria",
+			input: `criteria "c1" { monitor "a" { level 1 when value > 1; } }
+criteria "c1" { monitor "a" { level 1 when value > 1; } }`,
+			code: CodeDuplicateCriteria,
+			line: 2,
+		},
+		{
+			name: "duplicate monitor",
+			input: `criteria "c1" {
+	monitor "a" { level 1 when value > 1; }
+	monitor "a" { level 2 when value > 2; }
+}`,
+			code: CodeDuplicateMonitor,
+			line: 3,
+		},
+		{
+			name: "duplicate level",
+			input: `criteria "c1" {
+	monitor "a" {
+		level 1 when value > 1;
+		level 1 when value > 2;
+	}
+}`,
+			code: CodeDuplicateLevel,
+			line: 4,
+		},
+		{
+			name:  "arithmetic condition",
+			input: `criteria "c1" { monitor "a" { level 1 when value + 1; } }`,
+			code:  CodeConditionNotBoolean,
+			line:  1,
+		},
+		{
+			name:  "function condition",
+			input: `criteria "c1" { monitor "a" { level 1 when abs(value); } }`,
+			code:  CodeConditionNotBoolean,
+			line:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				ast, diagnostics := Parse(strings.NewReader(tt.input))
+				require.Nil(t, ast)
+				require.Len(t, diagnostics, 1)
+				require.ErrorIs(t, diagnostics[0], tt.code)
+				require.Equal(t, tt.line, diagnostics[0].Start.Line)
+			},
+		)
+	}
+
+	t.Run(
+		"negative level",
+		func(t *testing.T) {
+			diagnostics := Validate(
+				&AlertConfiguration{
+					Criterias: []*Criteria{
+						{
+							Name: "c1",
+							Monitors: []*Monitor{
+								{
+									ColumnName: "a",
+									Rules: []*Rule{
+										{
+											Level:     -1,
+											Condition: newLiteral(boolValue(true), _dslTrue),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			)
+			require.Len(t, diagnostics, 1)
+			require.ErrorIs(t, diagnostics[0], CodeInvalidLevel)
+
+			ast, diagnostics := Parse(strings.NewReader(`criteria "c1" { monitor "a" { level -1 when value > 1; } }`))
+			require.Nil(t, ast)
+			require.Len(t, diagnostics, 1)
+			require.ErrorIs(t, diagnostics[0], CodeInvalidLevel)
+		},
+	)
+
+	t.Run(
+		"variables against the dataset columns",
+		func(t *testing.T) {
+			input := `criteria "c1" {
+	monitor "amount" { level 1 when value > col_limit and col_missing > 1; }
+	monitor "price" { level 1 when value > 1; }
+}`
+
+			ast, diagnostics := Parse(strings.NewReader(input))
+			require.Empty(t, diagnostics, "columns are not checked without a dataset")
+			require.NotNil(t, ast)
+
+			_, diagnostics = ParseWithParams(
+				strings.NewReader(input),
+				&ParamsParse{
+					Columns: []string{"amount", "col_limit"},
+				},
+			)
+			require.Len(t, diagnostics, 2)
+			require.ErrorIs(t, diagnostics[0], CodeUndefinedVariable)
+			require.Contains(t, diagnostics[0].Message, "col_missing")
+			require.Equal(t, 56, diagnostics[0].Start.Column)
+			require.ErrorIs(t, diagnostics[1], CodeUndefinedVariable)
+			require.Contains(t, diagnostics[1].Message, "price")
+		},
+	)
+	t.Run(
+		"misspelled value without dataset columns",
+		func(t *testing.T) {
+			input := `criteria "c1" { monitor "amount" { level 1 when vaule > 5 and limit > 1; } }`
+
+			ast, diagnostics := Parse(strings.NewReader(input))
+			require.NotNil(t, ast, "a warning only")
+			require.Len(t, diagnostics, 1)
+			require.Equal(t, SeverityWarning, diagnostics[0].Severity)
+			require.ErrorIs(t, diagnostics[0], CodeUndefinedVariable)
+			require.Contains(t, diagnostics[0].Message, "vaule")
+			require.Equal(t, "did you mean `value`?", diagnostics[0].Hint)
+
+			_, diagnostics = ParseWithParams(
+				strings.NewReader(input),
+				&ParamsParse{
+					Columns: []string{"amount", "limit"},
+				},
+			)
+			require.Len(t, diagnostics, 1)
+			require.Equal(t, SeverityError, diagnostics[0].Severity)
+		},
+	)
+}
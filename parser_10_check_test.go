@@ -0,0 +1,127 @@
+package dslalert
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		condition string
+		code      Code
+		column    int // of the condition start, 1 based
+		message   string
+	}{
+		{`value > 1 + "a"`, CodeTypeMismatch, 9, "left side of '+' must be string, got number"},
+		{`1 > "a"`, CodeTypeMismatch, 1, "cannot order number and string with '>'"},
+		{`value == 1 or "a" == 2`, CodeTypeMismatch, 15, "'==' compares string and number"},
+		{`value contains 5`, CodeTypeMismatch, 16, "right side of 'contains' must be string, got number"},
+		{`value in 5`, CodeTypeMismatch, 10, "right side of 'in' must be list, got number"},
+		{`value between 1 and "z"`, CodeTypeMismatch, 1, "cannot order number and string with 'between'"},
+		{`not abs(value)`, CodeTypeMismatch, 5, "operand of 'not' must be bool, got number"},
+		{`value > 5 and 3`, CodeTypeMismatch, 15, "right side of 'and' must be bool, got number"},
+		{`if value then 1 else 2`, CodeConditionNotBoolean, 1, "condition must be a bool, got number"},
+		{`value * 2`, CodeConditionNotBoolean, 1, "got number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.condition,
+			func(t *testing.T) {
+				prefix := `criteria "c1" { monitor "a" { level 1 when `
+
+				_, diagnostics := Parse(strings.NewReader(prefix + tt.condition + "; } }"))
+				require.Len(t, diagnostics, 1, diagnostics.Error())
+				require.ErrorIs(t, diagnostics[0], tt.code)
+				require.Equal(t, len(prefix)+tt.column, diagnostics[0].Start.Column)
+				require.Contains(t, diagnostics[0].Message, tt.message)
+			},
+		)
+	}
+
+	t.Run(
+		"kinds of each node",
+		func(t *testing.T) {
+			ast, diagnostics := Parse(strings.NewReader(
+				`criteria "c1" { monitor "a" { level 1 when abs(value - 1) > 2 and region + "x" == "eux"; } }`,
+			))
+			require.Empty(t, diagnostics)
+
+			info, diagnostics := Check(ast)
+			require.Empty(t, diagnostics)
+
+			condition := ast.Criterias[0].Monitors[0].Rules[0].Condition.(*ExpressionBinary)
+			comparison := condition.LefthandSide.(*ExpressionBinary)
+			call := comparison.LefthandSide.(*ExpressionCall)
+			concatenation := condition.RighthandSide.(*ExpressionBinary).LefthandSide
+
+			require.Equal(t, KindBool, info.KindOf(condition))
+			require.Equal(t, KindNumber, info.KindOf(call))
+			require.Equal(t, KindNumber, info.KindOf(call.Arguments[0]))
+			require.Equal(t, KindAny, info.KindOf(call.Arguments[0].(*ExpressionBinary).LefthandSide))
+			require.Equal(t, KindString, info.KindOf(concatenation))
+
+			require.True(t, info.isChecked(comparison), "number > number")
+			require.False(t, info.isChecked(call.Arguments[0]), "value is only known per row")
+		},
+	)
+
+	t.Run(
+		"concurrent checks of one configuration",
+		func(t *testing.T) {
+			ast, diagnostics := Parse(strings.NewReader(
+				`criteria "c1" { monitor "a" { level 1 when abs(value) - 1 > 2 * 3 and not (abs(value) >= 9); } }`,
+			))
+			require.Empty(t, diagnostics)
+
+			formatted := Format(ast)
+
+			var wg sync.WaitGroup
+
+			wg.Add(8)
+
+			for range 8 {
+				go func() {
+					defer wg.Done()
+
+					_, _ = Check(ast)
+					_ = Validate(ast)
+				}()
+			}
+
+			wg.Wait()
+
+			require.Equal(t, formatted, Format(ast))
+		},
+	)
+
+	t.Run(
+		"checked operations evaluate like unchecked ones",
+		func(t *testing.T) {
+			for _, condition := range []string{
+				`abs(value) - 1 > 2 * 3`,
+				`-abs(value) < -2`,
+				`not (abs(value) >= 9)`,
+				`"a" + "b" < "b"`,
+				`"abc" contains "b"`,
+			} {
+				expression := parseExpr(condition)
+
+				checked := compileCondition(expression, checkCondition(expression))
+
+				for _, cell := range []float64{-10, 0, 7, 9} {
+					matchChecked, errChecked := checked(rowValue(cell))
+					require.NoError(t, errChecked)
+
+					matchUnchecked, errUnchecked := evaluateCondition(expression, rowValue(cell))
+					require.NoError(t, errUnchecked)
+
+					require.Equal(t, matchUnchecked, matchChecked, "%s with %v", condition, cell)
+				}
+			}
+		},
+	)
+}
@@ -0,0 +1,144 @@
+package dslalert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueIntervals(t *testing.T) {
+	tests := []struct {
+		condition string
+		want      string
+	}{
+		{"value > 5", "(5, +Inf)"},
+		{"5 >= value", "(-Inf, 5]"},
+		{"value > 5 and value < 10", "(5, 10)"},
+		{"value < 0 or value > 100", "(-Inf, 0) ∪ (100, +Inf)"},
+		{"not (value between 0 and 100)", "(-Inf, 0) ∪ (100, +Inf)"},
+		{"value != 5", "(-Inf, 5) ∪ (5, +Inf)"},
+		{"value == 10 within 2", "[8, 12]"},
+		{"value in [3, 1, 2]", "[1, 1] ∪ [2, 2] ∪ [3, 3]"},
+		{"value > -5 and value <= 5", "(-5, 5]"},
+		{"value > 10 and value < 5", ""},
+	}
+
+	for ix, tt := range tests {
+		t.Run(
+			fmt.Sprintf("%d. %s", ix+1, tt.condition),
+			func(t *testing.T) {
+				result, isAnalyzed := valueIntervals(parseExpr(tt.condition))
+				require.True(t, isAnalyzed)
+				require.Equal(t, tt.want, result.String())
+			},
+		)
+	}
+
+	t.Run(
+		"not analyzed",
+		func(t *testing.T) {
+			for _, condition := range []string{
+				"value > limit",
+				"abs(value) > 5",
+				"value * 2 > 5",
+				`value contains "x"`,
+			} {
+				_, isAnalyzed := valueIntervals(parseExpr(condition))
+				require.False(t, isAnalyzed, condition)
+			}
+		},
+	)
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		codes []Code
+		lines []int
+	}{
+		{
+			name: "1. monotonic thresholds",
+			rules: `
+		level 3 when value > 20;
+		level 2 when value > 10;
+		level 1 when value > 5;`,
+		},
+		{
+			name: "2. lower level shadowed",
+			rules: `
+		level 2 when value > 5;
+		level 1 when value > 10;`,
+			codes: []Code{CodeUnreachableLevel},
+			lines: []int{4},
+		},
+		{
+			name: "3. condition matching nothing",
+			rules: `
+		level 1 when value > 10 and value < 5;`,
+			codes: []Code{CodeUnreachableLevel},
+			lines: []int{3},
+		},
+		{
+			name: "4. off by one gap",
+			rules: `
+		level 2 when value > 10;
+		level 1 when value > 5 and value < 10;`,
+			codes: []Code{CodeLevelGap},
+			lines: []int{2},
+		},
+		{
+			name: "5. normal range is no gap",
+			rules: `
+		level 2 when value < -10 or value > 110;
+		level 1 when value < 0 or value > 100;`,
+		},
+		{
+			name: "6. non monotonic thresholds",
+			rules: `
+		level 3 when value > 20;
+		level 2 when value > 5 and value <= 10;
+		level 1 when value > 10;`,
+			codes: []Code{CodeNonMonotonic},
+			lines: []int{5},
+		},
+		{
+			name: "7. other columns are not analyzed",
+			rules: `
+		level 2 when value > limit;
+		level 1 when value > 10;`,
+		},
+		{
+			name: "8. unreachable despite rules not analyzed",
+			rules: `
+		level 3 when value > limit;
+		level 2 when value > 5;
+		level 1 when value > 10;`,
+			codes: []Code{CodeUnreachableLevel},
+			lines: []int{5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				input := "criteria \"c1\" {\n\tmonitor \"a\" {" + tt.rules + "\n\t}\n}"
+
+				ast, diagnostics := Parse(strings.NewReader(input))
+				require.Empty(t, diagnostics)
+
+				findings := Lint(ast)
+				require.Len(t, findings, len(tt.codes), findings.Error())
+				require.False(t, findings.HasErrors())
+
+				for ix, finding := range findings {
+					require.ErrorIs(t, finding, tt.codes[ix])
+					require.Equal(t, tt.lines[ix], finding.Start.Line)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,224 @@
+package dslalert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const _inputLint = `// Orders of the day.
+criteria "orders" {
+	// Order amount.
+	monitor "amount" {
+		level 2 when value > 86400;
+		level 1 when value > 100 or value == 0.3;
+	}
+
+	monitor "delay" {
+		level 3 when value > 86400;
+	}
+}
+`
+
+func lintInput(t *testing.T, input string, params *ParamsLint) Diagnostics {
+	t.Helper()
+
+	ast, diagnostics := Parse(strings.NewReader(input))
+	require.Empty(t, diagnostics)
+
+	linter, errNew := NewLinter(params)
+	require.NoError(t, errNew)
+
+	return linter.Lint(ast)
+}
+
+func codesOf(diagnostics Diagnostics) []Code {
+	result := make([]Code, len(diagnostics))
+
+	for ix, diagnostic := range diagnostics {
+		result[ix] = diagnostic.Code
+	}
+
+	return result
+}
+
+func TestLinter(t *testing.T) {
+	t.Run(
+		"1. default checks",
+		func(t *testing.T) {
+			findings := lintInput(t, _inputLint, &ParamsLint{})
+
+			require.Equal(
+				t,
+				[]Code{CodeFloatEquality, CodeLevelStart, CodeRepeatedNumber},
+				codesOf(findings),
+			)
+			require.Equal(t, SeverityWarning, findings[0].Severity)
+			require.Equal(t, 6, findings[0].Start.Line)
+			require.Equal(t, SeverityInfo, findings[2].Severity)
+			require.Equal(t, 10, findings[2].Start.Line)
+			require.NoError(t, findings.Err())
+		},
+	)
+
+	t.Run(
+		"2. enable, disable and severities",
+		func(t *testing.T) {
+			findings := lintInput(
+				t,
+				_inputLint,
+				&ParamsLint{
+					Enable:  []string{"missing-doc"},
+					Disable: []string{"DSL022", "level-start"},
+					Severities: map[string]Severity{
+						"float-equality": SeverityError,
+					},
+				},
+			)
+
+			require.Equal(
+				t,
+				[]Code{CodeFloatEquality, CodeMissingDoc},
+				codesOf(findings),
+			)
+			require.Equal(t, SeverityError, findings[0].Severity)
+			require.Error(t, findings.Err())
+			require.Equal(t, 9, findings[1].Start.Line)
+		},
+	)
+
+	t.Run(
+		"3. too many monitors",
+		func(t *testing.T) {
+			findings := lintInput(
+				t,
+				_inputLint,
+				&ParamsLint{
+					Disable:         []string{"float-equality", "level-start", "repeated-number"},
+					MonitorsMaximum: 1,
+				},
+			)
+
+			require.Equal(t, []Code{CodeTooManyMonitors}, codesOf(findings))
+			require.Equal(t, 2, findings[0].Start.Line)
+		},
+	)
+
+	t.Run(
+		"4. unknown check",
+		func(t *testing.T) {
+			linter, errNew := NewLinter(
+				&ParamsLint{
+					Disable: []string{"no-such-check"},
+				},
+			)
+			require.Error(t, errNew)
+			require.Nil(t, linter)
+		},
+	)
+
+	t.Run(
+		"5. severity of a check set twice",
+		func(t *testing.T) {
+			linter, errNew := NewLinter(
+				&ParamsLint{
+					Severities: map[string]Severity{
+						"float-equality": SeverityError,
+						"DSL026":         SeverityInfo,
+					},
+				},
+			)
+			require.ErrorContains(t, errNew, "names the check already named 'DSL026'")
+			require.Nil(t, linter)
+		},
+	)
+
+	t.Run(
+		"6. all checks are listed",
+		func(t *testing.T) {
+			checks := LintChecks()
+			require.Len(t, checks, 8)
+
+			for _, check := range checks {
+				_, exists := lookupLintCheck(check.Name)
+				require.True(t, exists, check.Name)
+			}
+		},
+	)
+}
+
+func TestLintSuppression(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		codes []Code
+	}{
+		{
+			name: "1. doc comment of the rule",
+			input: `criteria "c1" {
+	monitor "a" {
+		level 1 when value == 0.3; // dsl:ignore float-equality
+	}
+}`,
+			codes: []Code{},
+		},
+		{
+			name: "2. monitor covers its rules",
+			input: `criteria "c1" {
+	// dsl:ignore DSL024, DSL026
+	monitor "a" {
+		level 2 when value == 0.3;
+	}
+}`,
+			codes: []Code{},
+		},
+		{
+			name: "3. only the named codes",
+			input: `criteria "c1" {
+	// dsl:ignore DSL024
+	monitor "a" {
+		level 2 when value == 0.3;
+	}
+}`,
+			codes: []Code{CodeFloatEquality},
+		},
+		{
+			name: "4. other nodes keep their findings",
+			input: `// dsl:ignore level-start
+criteria "c1" {
+	monitor "a" {
+		level 2 when value > 5;
+	}
+}
+
+criteria "c2" {
+	monitor "a" {
+		level 2 when value > 6;
+	}
+}`,
+			codes: []Code{CodeLevelStart},
+		},
+		{
+			name: "5. comment on the opening brace",
+			input: `criteria "c1" {
+	monitor "a" { // dsl:ignore level-start, level-gap
+		level 2 when value > 5;
+		level 4 when value > 9;
+	}
+}`,
+			codes: []Code{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				findings := lintInput(t, tt.input, &ParamsLint{})
+
+				require.Equal(t, tt.codes, codesOf(findings), findings.Error())
+			},
+		)
+	}
+}
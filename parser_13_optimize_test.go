@@ -0,0 +1,232 @@
+package dslalert
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"value > 60 * 60 * 24", "(value > 86400)"},
+		{"5 < value", "(value > 5)"},
+		{"5 == value within 0.1", "(value == 5 within 0.1)"},
+		{"value * 100 / 100 > 5", "((+value) > 5)"},
+		{"value * 2 * 3 > 5", "((value * 6) > 5)"},
+		{"value / 2 / 5 > 5", "((value / 10) > 5)"},
+		{"value + 1 - 1 == 5", "((+value) == 5)"},
+		{"abs(value) * 1 > 5", "(abs(value) > 5)"},
+		{"0 + limit - 0 < 5", "((+limit) < 5)"},
+		{"not (value == 5)", "(value != 5)"},
+		{"!(value in [1, 2])", "(value not in [1, 2])"},
+		{"not (value between 1 and 2)", "(value not between 1 and 2)"},
+		{"not not (value != 5)", "(value != 5)"},
+		{"true and value > 5", "(value > 5)"},
+		{"value > 5 or false", "(value > 5)"},
+		{"false and value / 0 > 1", "false"},
+		{"1 > 2 or value > 5", "(value > 5)"},
+		{"value > (if 2 > 1 then 50 else 100)", "(value > 50)"},
+		{"value > round(10 / 4, 1) - -1", "(value > 3.5)"},
+		{"value in [1 + 1, 3]", "(value in [2, 3])"},
+		{`name == "a" + "b"`, `(name == "ab")`},
+
+		// kept, as simplifying would change the rows failing.
+		{"true and value", "(true and value)"},
+		{"value / 0 > 1", "((value / 0) > 1)"},
+		{"1 / 0 > value", "((1 / 0) > value)"},
+		{"value * 1 == 5", "((+value) == 5)"},
+		{"not weekend", "(not weekend)"},
+		{"not (value > 5)", "(not (value > 5))"},
+	}
+
+	for ix, tt := range tests {
+		t.Run(
+			fmt.Sprintf("%d. %s", ix+1, tt.input),
+			func(t *testing.T) {
+				require.Equal(t, tt.want, optimizeCondition(parseExpr(tt.input)).String())
+			},
+		)
+	}
+}
+
+func TestOptimizeEvaluatesLikeTree(t *testing.T) {
+	row := func(value string) *rowContext {
+		return &rowContext{
+			columns: map[string]int{"limit": 0, "weekend": 1, "name": 2},
+			record:  []string{"20", "true", "abc"},
+			value:   parseCell(value),
+		}
+	}
+
+	conditions := append(
+		[]string{
+			"value * 100 / 100 > 5",
+			"value + 0 == 7",
+			"value * 1 != 7",
+			"0 + value - 0 >= 3.5",
+			"not (value >= 7)",
+			"not (value not in [0, 7])",
+			"not not (value > 1)",
+			"true and value > 1",
+			"value > 1 and true",
+			"false or value > 1",
+			"3 <= value",
+			"--value > 1",
+		},
+		_conditionsCompile...,
+	)
+
+	for ix, condition := range conditions {
+		t.Run(
+			fmt.Sprintf("%d. %s", ix+1, condition),
+			func(t *testing.T) {
+				expression := parseExpr(condition)
+				optimized := optimizeCondition(parseExpr(condition))
+
+				for _, cell := range []string{"-10", "0", "3.5", "7", "20", "NaN", "abc", "", "true"} {
+					matchTree, errTree := evaluateCondition(expression, row(cell))
+					matchOptimized, errOptimized := evaluateCondition(optimized, row(cell))
+
+					if errTree != nil {
+						require.Error(t, errOptimized, cell)
+
+						continue
+					}
+
+					require.NoError(t, errOptimized, cell)
+					require.Equal(t, matchTree, matchOptimized, cell)
+				}
+			},
+		)
+	}
+}
+
+func TestOptimizeExtremeFactors(t *testing.T) {
+	tests := []struct {
+		condition string
+		want      string
+	}{
+		{"value / 1e-200 / 1e-200 > 0", "(((value / 1e-200) / 1e-200) > 0)"},
+		{"value * 1e200 * 1e200 > 0", "(((value * 1e200) * 1e200) > 0)"},
+		{"value * 1e300 / 1e300 > 0", "(((value * 1e300) / 1e300) > 0)"},
+		{"value * 1e-160 * 1e-160 > 0", "(((value * 1e-160) * 1e-160) > 0)"},
+		{"value * 1e60 / 1e60 > 0", "((+value) > 0)"},
+	}
+
+	for ix, tt := range tests {
+		t.Run(
+			fmt.Sprintf("%d. %s", ix+1, tt.condition),
+			func(t *testing.T) {
+				expression := parseExpr(tt.condition)
+				optimized := optimizeCondition(parseExpr(tt.condition))
+				require.Equal(t, tt.want, optimized.String())
+
+				compiled := compileCondition(optimized, checkCondition(optimized))
+				vm, entry := bytecodeCondition(t, optimized.String())
+
+				for _, cell := range []float64{1, -1, 1e-300, 1e300} {
+					matchTree, errTree := evaluateCondition(expression, rowValue(cell))
+					require.NoError(t, errTree, cell)
+
+					matchCompiled, errCompiled := compiled(rowValue(cell))
+					require.NoError(t, errCompiled, cell)
+					require.Equal(t, matchTree, matchCompiled, cell)
+
+					matchVM, errVM := vm.run(entry, rowValue(cell))
+					require.NoError(t, errVM, cell)
+					require.Equal(t, matchTree, matchVM, cell)
+				}
+			},
+		)
+	}
+}
+
+func TestOptimizeConfiguration(t *testing.T) {
+	input := `
+		criteria "c1" {
+			monitor "amount" {
+				level 2 when value > 60 * 60 * 24;
+				level 1 when 10 < value;
+				level 3 when value > limit;
+			}
+		}`
+
+	ast, errs := Parse(strings.NewReader(input))
+	require.Empty(t, errs)
+
+	formatted := Format(ast)
+
+	stdout := os.Stdout
+
+	reader, writer, errPipe := os.Pipe()
+	require.NoError(t, errPipe)
+
+	os.Stdout = writer
+
+	optimized := OptimizeWithParams(
+		ast,
+		&ParamsOptimize{
+			IsDebugMode: true,
+		},
+	)
+
+	os.Stdout = stdout
+	require.NoError(t, writer.Close())
+
+	debug, errRead := io.ReadAll(reader)
+	require.NoError(t, errRead)
+
+	require.Equal(
+		t,
+		`OPTIMIZE: criteria "c1", monitor "amount", level 2: (value > ((60 * 60) * 24)) => (value > 86400)`+"\n"+
+			`OPTIMIZE: criteria "c1", monitor "amount", level 1: (10 < value) => (value > 10)`+"\n",
+		string(debug),
+	)
+
+	require.Equal(t, formatted, Format(ast), "input unchanged")
+	require.Contains(t, Format(optimized), "level 2 when value > 86400;")
+
+	dataset := []string{"amount,limit", "5,100", "11,100", "90000,100000", "90000,50"}
+
+	want, errEvaluate := EvaluateCriteria(ast.Criterias[0], dataset)
+	require.NoError(t, errEvaluate)
+
+	got, errEvaluate := EvaluateCriteria(optimized.Criterias[0], dataset)
+	require.NoError(t, errEvaluate)
+	require.Equal(t, want, got)
+}
+
+const _conditionOptimize = "value * 100 / 100 > 60 * 60 * 24 or 5 > value"
+
+func BenchmarkEvaluateUnoptimized(b *testing.B) {
+	expression := parseExpr(_conditionOptimize)
+
+	compiled := compileCondition(expression, checkCondition(expression))
+	row := rowValue(15)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, _ = compiled(row)
+	}
+}
+
+func BenchmarkEvaluateOptimized(b *testing.B) {
+	optimized := optimizeCondition(parseExpr(_conditionOptimize))
+
+	compiled := compileCondition(optimized, checkCondition(optimized))
+	row := rowValue(15)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, _ = compiled(row)
+	}
+}
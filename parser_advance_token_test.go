@@ -18,10 +18,10 @@ func TestEmptyInput(t *testing.T) {
 	require.NotEmpty(t, errors, "should return error for empty input")
 	require.Regexp(t,
 		regexp.MustCompile(`(?i)^input is empty$`), // Exact match, case-insensitive
-		errors[0],
+		errors[0].Message,
 
 		"Expected empty input error\nGot: %q",
-		errors[0],
+		errors[0].Message,
 	)
 }
 